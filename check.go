@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/collector"
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+)
+
+// checkTimeout bounds the request to a single endpoint in the check command.
+const checkTimeout = 30 * time.Second
+
+// runCheck calls the endpoints of the enabled collectors of the configured
+// OPNsense targets once, prints the results and returns the exit code of the check command.
+func runCheck(logger *slog.Logger) int {
+	targets := make(map[string]options.TargetConfig)
+
+	if options.SingleTargetConfigured() {
+		conf, err := options.Load()
+		if err != nil {
+			logger.Error("failed to load configuration", "err", err)
+			return 1
+		}
+		targets[conf.InstanceLabel] = *conf
+	}
+
+	if *options.ProbeTargetsFile != "" {
+		conf, err := options.LoadTargets(*options.ProbeTargetsFile)
+		if err != nil {
+			logger.Error("failed to load probe targets", "err", err)
+			return 1
+		}
+		for name, target := range conf.Targets {
+			targets[name] = target
+		}
+	}
+
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exitCode := 0
+	for _, name := range names {
+		target := targets[name]
+
+		c, err := collector.BuildFromTarget(target, version, logger)
+		if err != nil {
+			logger.Error("failed to create collector", "target", name, "err", err)
+			exitCode = 1
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		endpoints := c.Endpoints(ctx)
+		cancel()
+
+		fmt.Printf("Target %s (%s://%s)\n\n", name, target.Protocol, target.Host)
+		if !checkEndpoints(context.Background(), os.Stdout, c.Client, endpoints) {
+			exitCode = 1
+		}
+		fmt.Println()
+	}
+
+	return exitCode
+}
+
+// checkEndpoints calls the endpoints once and writes a table
+// with the status, latency and dependent collectors of each endpoint.
+// It reports whether all endpoints responded successfully.
+func checkEndpoints(ctx context.Context, out io.Writer, client *opnsense.Client, names []opnsense.EndpointName) bool {
+	endpoints := client.Endpoints()
+
+	names = slices.Clone(names)
+	sort.Slice(names, func(i, j int) bool { return endpoints[names[i]] < endpoints[names[j]] })
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENDPOINT\tSTATUS\tLATENCY\tCOLLECTORS\tRESULT")
+
+	ok := true
+	for _, name := range names {
+		endpointCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		status, latency, err := client.CheckEndpoint(endpointCtx, name)
+		cancel()
+
+		dependents := strings.Join(collector.EndpointDependents(name), ",")
+		if dependents == "" {
+			dependents = "-"
+		}
+
+		result := "ok"
+		if err != nil {
+			ok = false
+			result = checkFailure(name, err)
+		}
+
+		statusText := "-"
+		if status != 0 {
+			statusText = fmt.Sprint(status)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			endpoints[name], statusText, latency.Round(time.Millisecond), dependents, result)
+	}
+	w.Flush()
+
+	return ok
+}
+
+// checkFailure describes why the request to the endpoint failed.
+// Authorization failures are translated into the missing OPNsense privilege.
+func checkFailure(name opnsense.EndpointName, err *opnsense.APICallError) string {
+	switch err.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		privilege := opnsense.EndpointPrivilege(name)
+		if privilege == "" {
+			return "access denied"
+		}
+		if err.StatusCode == http.StatusUnauthorized {
+			return fmt.Sprintf("access denied: invalid API key or missing privilege %q", privilege)
+		}
+		return fmt.Sprintf("access denied: missing privilege %q", privilege)
+	case http.StatusNotFound:
+		return "not found: the endpoint or its plugin is not available on this OPNsense release"
+	}
+
+	message := strings.TrimSpace(err.Message)
+	if len(message) > 120 {
+		message = message[:120] + "..."
+	}
+	return "failed: " + message
+}
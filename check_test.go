@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/collector"
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/common/promslog"
+)
+
+func TestCheckEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    *opnsensetest.Fault
+		expectOK bool
+		expected string
+	}{
+		{
+			name:     "All endpoints reachable",
+			expectOK: true,
+			expected: "api/diagnostics/interface/search_arp",
+		},
+		{
+			name:     "Missing privilege",
+			fault:    &opnsensetest.Fault{StatusCode: http.StatusForbidden},
+			expectOK: false,
+			expected: `missing privilege "Diagnostics: ARP Table"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := opnsensetest.NewServer(t, opnsensetest.Releases[len(opnsensetest.Releases)-1])
+			if tc.fault != nil {
+				server.SetFault("arp", *tc.fault)
+			}
+
+			client := server.Client(t)
+			c, err := collector.New(client, promslog.NewNopLogger(), "test")
+			if err != nil {
+				t.Fatalf("expected no error when creating collector, got %v", err)
+			}
+
+			var out bytes.Buffer
+			ok := checkEndpoints(context.Background(), &out, client, c.Endpoints(context.Background()))
+			if ok != tc.expectOK {
+				t.Errorf("expected check result %t, got %t:\n%s", tc.expectOK, ok, out.String())
+			}
+			if !strings.Contains(out.String(), tc.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestCheckEnabledEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		release    string
+		options    []collector.Option
+		checked    []string
+		notChecked []string
+	}{
+		{
+			name:       "Disabled collector",
+			release:    "25.7",
+			options:    []collector.Option{collector.WithoutCollector(collector.ArpTableSubsystem)},
+			checked:    []string{"api/core/system/status", "api/routing/settings/searchGateway"},
+			notChecked: []string{"api/diagnostics/interface/search_arp", "api/core/firmware/info"},
+		},
+		{
+			name:    "Opt-in collector",
+			release: "25.7",
+			options: []collector.Option{collector.WithExplicitCollector(collector.FirewallLogSubsystem)},
+			checked: []string{"api/diagnostics/firewall/log"},
+		},
+		{
+			name:       "Collector disabled by plugin detection",
+			release:    "24.7",
+			options:    []collector.Option{collector.WithPluginDetection(time.Hour)},
+			checked:    []string{"api/core/firmware/info", "api/kea/leases4/search"},
+			notChecked: []string{"api/dnsmasq/leases/search", "api/diagnostics/firewall/log"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := opnsensetest.NewServer(t, tc.release)
+			client := server.Client(t)
+			c, err := collector.New(client, promslog.NewNopLogger(), "test", tc.options...)
+			if err != nil {
+				t.Fatalf("expected no error when creating collector, got %v", err)
+			}
+
+			var out bytes.Buffer
+			if !checkEndpoints(context.Background(), &out, client, c.Endpoints(context.Background())) {
+				t.Errorf("expected the check to succeed:\n%s", out.String())
+			}
+			for _, path := range tc.checked {
+				if !strings.Contains(out.String(), path+" ") {
+					t.Errorf("expected %s to be checked, got:\n%s", path, out.String())
+				}
+			}
+			for _, path := range tc.notChecked {
+				if strings.Contains(out.String(), path+" ") {
+					t.Errorf("expected %s not to be checked, got:\n%s", path, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/collector"
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/AthennaMind/opnsense-exporter/internal/probe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// exporter holds the metrics and probe handlers that are
+// rebuilt from the configuration on every reload.
+type exporter struct {
+	log *slog.Logger
+
+	// registry holds the metrics that are kept across reloads,
+	// like the exporter's own process and reload metrics.
+	registry *prometheus.Registry
+
+	reloadMutex sync.Mutex
+	mutex       sync.RWMutex
+	metrics     http.Handler
+	probe       *probe.Handler
+	collector   *collector.Collector
+	// inflight counts the requests served by the active handlers,
+	// so the replaced collectors are only stopped after their last scrape.
+	inflight *sync.WaitGroup
+
+	reloadSuccess          prometheus.Gauge
+	reloadSuccessTimestamp prometheus.Gauge
+}
+
+func newExporter(registry *prometheus.Registry, log *slog.Logger) *exporter {
+	e := exporter{
+		log:      log,
+		registry: registry,
+		inflight: &sync.WaitGroup{},
+	}
+
+	e.reloadSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "opnsense",
+		Name:      "exporter_config_last_reload_successful",
+		Help:      "Whether the last configuration reload attempt was successful. (1 = yes, 0 = no)",
+	})
+	e.reloadSuccessTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "opnsense",
+		Name:      "exporter_config_last_reload_success_timestamp_seconds",
+		Help:      "Timestamp of the last successful configuration reload.",
+	})
+
+	registry.MustRegister(e.reloadSuccess, e.reloadSuccessTimestamp)
+
+	return &e
+}
+
+// build assembles new metrics and probe handlers from the current configuration.
+// The returned collector and probe handler, if any, must be stopped when they are replaced.
+func (e *exporter) build() (http.Handler, *collector.Collector, *probe.Handler, error) {
+	var collectorInstance *collector.Collector
+
+	if options.SingleTargetConfigured() {
+		target, err := options.Load()
+		if err != nil {
+			return nil, nil, nil, errors.Join(fmt.Errorf("failed to assemble OPNsense configuration"), err)
+		}
+
+		collectorInstance, err = collector.NewFromTarget(*target, version, e.log)
+		if err != nil {
+			return nil, nil, nil, errors.Join(fmt.Errorf("failed to construct the collector"), err)
+		}
+	}
+
+	var probeHandler *probe.Handler
+	if *options.ProbeTargetsFile != "" {
+		targetsConfig, err := options.LoadTargets(*options.ProbeTargetsFile)
+		if err == nil {
+			probeHandler, err = probe.NewHandler(targetsConfig, version, e.log)
+		}
+		if err != nil {
+			if collectorInstance != nil {
+				collectorInstance.Stop()
+			}
+			return nil, nil, nil, errors.Join(fmt.Errorf("failed to load probe targets"), err)
+		}
+	}
+
+	metricsHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gatherers := prometheus.Gatherers{e.registry}
+
+		if collectorInstance != nil {
+			ctx, cancel, err := collector.ScrapeContext(r, *options.TimeoutOffset)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer cancel()
+
+			scrape, err := collectorInstance.WithContext(ctx, r.URL.Query()["collect[]"]...)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(scrape)
+			gatherers = append(gatherers, registry)
+		}
+
+		promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+
+	return metricsHandler, collectorInstance, probeHandler, nil
+}
+
+// reload re-reads the configuration. The handlers are replaced only
+// when the new configuration is valid, otherwise the previous ones stay active.
+func (e *exporter) reload() error {
+	e.reloadMutex.Lock()
+	defer e.reloadMutex.Unlock()
+
+	metricsHandler, collectorInstance, probeHandler, err := e.build()
+	if err != nil {
+		e.reloadSuccess.Set(0)
+		return err
+	}
+
+	e.mutex.Lock()
+	previousCollector, previousProbe, previousInflight := e.collector, e.probe, e.inflight
+	e.metrics = metricsHandler
+	e.collector = collectorInstance
+	e.probe = probeHandler
+	e.inflight = &sync.WaitGroup{}
+	e.mutex.Unlock()
+
+	// no request can start on the previous handlers anymore,
+	// wait for the running ones before their collectors are stopped
+	if previousInflight != nil {
+		previousInflight.Wait()
+	}
+	if previousCollector != nil {
+		previousCollector.Stop()
+	}
+	if previousProbe != nil {
+		previousProbe.Stop()
+	}
+
+	e.reloadSuccess.Set(1)
+	e.reloadSuccessTimestamp.SetToCurrentTime()
+	return nil
+}
+
+// serveMetrics serves the metrics path with the handler of the active configuration.
+func (e *exporter) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mutex.RLock()
+	handler, inflight := e.metrics, e.inflight
+	inflight.Add(1)
+	e.mutex.RUnlock()
+	defer inflight.Done()
+
+	handler.ServeHTTP(w, r)
+}
+
+// serveProbe serves the /probe endpoint with the handler of the active configuration.
+func (e *exporter) serveProbe(w http.ResponseWriter, r *http.Request) {
+	e.mutex.RLock()
+	handler, inflight := e.probe, e.inflight
+	inflight.Add(1)
+	e.mutex.RUnlock()
+	defer inflight.Done()
+
+	if handler == nil {
+		http.NotFound(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// serveReload triggers a configuration reload on POST or PUT /-/reload.
+func (e *exporter) serveReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		w.Header().Set("Allow", "POST, PUT")
+		http.Error(w, "only POST or PUT requests allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := e.reload(); err != nil {
+		e.log.Error("failed to reload configuration; keeping the previous one", "err", err)
+		http.Error(w, fmt.Sprintf("failed to reload configuration: %s", err), http.StatusInternalServerError)
+		return
+	}
+	e.log.Info("configuration reloaded")
+}
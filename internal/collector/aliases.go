@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type aliasesCollector struct {
+	log *slog.Logger
+
+	entries    *prometheus.Desc
+	enabled    *prometheus.Desc
+	lastUpdate *prometheus.Desc
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &aliasesCollector{
+			subsystem: AliasesSubsystem,
+		}
+	})
+}
+
+func (c *aliasesCollector) Name() string {
+	return c.subsystem
+}
+
+func (c *aliasesCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.entries = buildPrometheusDesc(c.subsystem, "entries",
+		"Number of entries in the pf table of the alias", []string{"alias", "type"})
+	c.enabled = buildPrometheusDesc(c.subsystem, "enabled",
+		"Whether the alias is enabled (1 = enabled, 0 = disabled)", []string{"alias", "type", "description"})
+	c.lastUpdate = buildPrometheusDesc(c.subsystem, "last_update_timestamp_seconds",
+		"Unix timestamp of the last successful update of the URL table or GeoIP alias", []string{"alias", "type"})
+}
+
+func (c *aliasesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entries
+	ch <- c.enabled
+	ch <- c.lastUpdate
+}
+
+func (c *aliasesCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"aliases"}
+}
+
+func (c *aliasesCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	aliases, err := client.FetchAliases(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, alias := range aliases {
+		enabled := 0.0
+		if alias.Enabled {
+			enabled = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.enabled,
+			prometheus.GaugeValue,
+			enabled,
+			alias.Name,
+			alias.Type,
+			alias.Description,
+			c.instance,
+		)
+
+		if alias.Entries >= 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.entries,
+				prometheus.GaugeValue,
+				alias.Entries,
+				alias.Name,
+				alias.Type,
+				c.instance,
+			)
+		}
+
+		if !alias.LastUpdated.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				c.lastUpdate,
+				prometheus.GaugeValue,
+				float64(alias.LastUpdated.Unix()),
+				alias.Name,
+				alias.Type,
+				c.instance,
+			)
+		}
+	}
+
+	return nil
+}
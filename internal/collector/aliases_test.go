@@ -0,0 +1,30 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAliasesMalformedValues(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	server.SetFault("aliases", opnsensetest.Fault{Body: []byte(`{"rows":[
+		{"uuid":"1","enabled":"1","name":"Spamhaus_DROP","type":"urltable","current_items":"812","last_updated":"2025-06-01 03:00:12"},
+		{"uuid":"2","enabled":"1","name":"GeoIP_Blocked","type":"geoip","current_items":"many","last_updated":"2025-06-02T01:30:00+00:00"}
+	]}`)})
+	c := newUpdateCollector(&aliasesCollector{subsystem: AliasesSubsystem}, server.Client(t))
+
+	if count := testutil.CollectAndCount(c, "opnsense_aliases_enabled"); count != 2 {
+		t.Errorf("expected both aliases to be collected, got %d series", count)
+	}
+	if count := testutil.CollectAndCount(c, "opnsense_aliases_entries"); count != 1 {
+		t.Errorf("expected the entries of the alias with a valid count only, got %d series", count)
+	}
+	if count := testutil.CollectAndCount(c, "opnsense_aliases_last_update_timestamp_seconds"); count != 1 {
+		t.Errorf("expected the update time of the alias with a valid time only, got %d series", count)
+	}
+	if c.err != nil {
+		t.Errorf("expected no error, got %v", c.err)
+	}
+}
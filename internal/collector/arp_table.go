@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 
@@ -16,8 +17,10 @@ type arpTableCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &arpTableCollector{
-		subsystem: ArpTableSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &arpTableCollector{
+			subsystem: ArpTableSubsystem,
+		}
 	})
 }
 
@@ -41,8 +44,12 @@ func (c *arpTableCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.entries
 }
 
-func (c *arpTableCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchArpTable()
+func (c *arpTableCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"arp"}
+}
+
+func (c *arpTableCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchArpTable(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// carpStates holds the states of a CARP virtual IP.
+var carpStates = []string{"master", "backup", "init"}
+
+type carpCollector struct {
+	log *slog.Logger
+
+	vipStatus       *prometheus.Desc
+	vipAdvbase      *prometheus.Desc
+	vipAdvskew      *prometheus.Desc
+	demotion        *prometheus.Desc
+	maintenanceMode *prometheus.Desc
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &carpCollector{
+			subsystem: CarpSubsystem,
+		}
+	})
+}
+
+func (c *carpCollector) Name() string {
+	return c.subsystem
+}
+
+func (c *carpCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	labels := []string{"interface", "vhid", "address", "description"}
+
+	c.vipStatus = buildPrometheusDesc(c.subsystem, "vip_status",
+		"Whether the CARP virtual IP is in the state (master, backup, init)",
+		append(labels, "state"))
+	c.vipAdvbase = buildPrometheusDesc(c.subsystem, "vip_advbase_seconds",
+		"Base advertisement interval of the CARP virtual IP in seconds", labels)
+	c.vipAdvskew = buildPrometheusDesc(c.subsystem, "vip_advskew",
+		"Advertisement skew of the CARP virtual IP, the node with the lowest skew becomes master", labels)
+	c.demotion = buildPrometheusDesc(c.subsystem, "demotion",
+		"Global CARP demotion counter, a node with a higher demotion does not become master", nil)
+	c.maintenanceMode = buildPrometheusDesc(c.subsystem, "maintenance_mode",
+		"Whether the CARP maintenance mode is enabled (1 = enabled, 0 = disabled)", nil)
+}
+
+func (c *carpCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.vipStatus
+	ch <- c.vipAdvbase
+	ch <- c.vipAdvskew
+	ch <- c.demotion
+	ch <- c.maintenanceMode
+}
+
+func (c *carpCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"carpStatus"}
+}
+
+func (c *carpCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchCarpStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, vip := range data.VIPs {
+		for _, state := range carpStates {
+			value := 0.0
+			if vip.Status == state {
+				value = 1.0
+			}
+			ch <- prometheus.MustNewConstMetric(c.vipStatus, prometheus.GaugeValue, value,
+				vip.Interface, vip.Vhid, vip.Address, vip.Description, state, c.instance)
+		}
+		ch <- prometheus.MustNewConstMetric(c.vipAdvbase, prometheus.GaugeValue, vip.Advbase,
+			vip.Interface, vip.Vhid, vip.Address, vip.Description, c.instance)
+		ch <- prometheus.MustNewConstMetric(c.vipAdvskew, prometheus.GaugeValue, vip.Advskew,
+			vip.Interface, vip.Vhid, vip.Address, vip.Description, c.instance)
+	}
+
+	maintenanceMode := 0.0
+	if data.MaintenanceMode {
+		maintenanceMode = 1.0
+	}
+	ch <- prometheus.MustNewConstMetric(c.demotion, prometheus.GaugeValue, data.Demotion, c.instance)
+	ch <- prometheus.MustNewConstMetric(c.maintenanceMode, prometheus.GaugeValue, maintenanceMode, c.instance)
+
+	return nil
+}
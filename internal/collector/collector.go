@@ -1,11 +1,15 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -19,18 +23,33 @@ const namespace = "opnsense"
 const instanceLabelName = "opnsense_instance"
 
 const (
-	ArpTableSubsystem   = "arp_table"
-	GatewaysSubsystem   = "gateways"
-	CronTableSubsystem  = "cron"
-	WireguardSubsystem  = "wireguard"
-	IPsecSubsystem      = "ipsec"
-	UnboundDNSSubsystem = "unbound_dns"
-	InterfacesSubsystem = "interfaces"
-	ProtocolSubsystem   = "protocol"
-	OpenVPNSubsystem    = "openvpn"
-	ServicesSubsystem   = "services"
-	FirewallSubsystem   = "firewall"
-	FirmwareSubsystem   = "firmware"
+	ArpTableSubsystem           = "arp_table"
+	GatewaysSubsystem           = "gateways"
+	CronTableSubsystem          = "cron"
+	WireguardSubsystem          = "wireguard"
+	IPsecSubsystem              = "ipsec"
+	UnboundDNSSubsystem         = "unbound_dns"
+	InterfacesSubsystem         = "interfaces"
+	ProtocolSubsystem           = "protocol"
+	OpenVPNSubsystem            = "openvpn"
+	ServicesSubsystem           = "services"
+	FirewallSubsystem           = "firewall"
+	FirmwareSubsystem           = "firmware"
+	DHCPv4Subsystem             = "dhcpv4"
+	DHCPv6Subsystem             = "dhcpv6"
+	KeaSubsystem                = "kea"
+	DnsmasqSubsystem            = "dnsmasq"
+	SystemSubsystem             = "system"
+	TemperatureSubsystem        = "temperature"
+	UnboundDNSBLSubsystem       = "unbound_dnsbl"
+	PFSubsystem                 = "pf"
+	FirewallRulesSubsystem      = "firewall_rules"
+	AliasesSubsystem            = "aliases"
+	FirewallLogSubsystem        = "firewall_log"
+	InterfacesOverviewSubsystem = "interfaces_overview"
+	CarpSubsystem               = "carp"
+	RoutesSubsystem             = "routes"
+	NdpSubsystem                = "ndp"
 )
 
 // CollectorInstance is the interface a service specific collectors must implement.
@@ -38,24 +57,71 @@ type CollectorInstance interface {
 	Register(namespace, isntance string, log *slog.Logger)
 	Name() string
 	Describe(ch chan<- *prometheus.Desc)
-	Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError
+	// Endpoints returns the OPNsense API endpoints that Update calls.
+	Endpoints() []opnsense.EndpointName
+	Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError
 }
 
-// collectorInstances is a list of collectorInstances that will be registered
-// from the init() function in each collector file
-var collectorInstances []CollectorInstance
+// collectorFactories is a list of constructors for the collector instances
+// that are registered from the init() function in each collector file.
+// Every Collector builds its own set of instances, so several Collectors
+// (one per scraped OPNsense target) can coexist in the same process.
+var collectorFactories []func() CollectorInstance
+
+// optInCollector is implemented by the collectors that are disabled by default,
+// e.g. because they keep state between the updates. They only run when they are
+// explicitly enabled with WithExplicitCollector.
+type optInCollector interface {
+	optIn()
+}
+
+// Defaults returns the names of all available collectors
+// and whether they are enabled by default.
+func Defaults() map[string]bool {
+	defaults := make(map[string]bool, len(collectorFactories))
+	for _, factory := range collectorFactories {
+		instance := factory()
+		_, optIn := instance.(optInCollector)
+		defaults[instance.Name()] = !optIn
+	}
+	return defaults
+}
 
 type Collector struct {
 	Client *opnsense.Client
-	mutex  sync.RWMutex
 	log    *slog.Logger
 
+	// healthMutex guards the health gauges that are set and collected by every scrape
+	healthMutex sync.Mutex
+	// updateMutexes serialize the updates of each collector, so concurrent scrapes
+	// only wait for each other when they update the same collector
+	updateMutexes map[string]*sync.Mutex
+
 	isUp                 prometheus.Gauge
 	firewallHealthStatus prometheus.Gauge
 	scrapes              prometheus.CounterVec
 	endpointErrors       prometheus.CounterVec
+	apiRequestDuration   *prometheus.HistogramVec
+	collectorDuration    *prometheus.Desc
+	collectorSuccess     *prometheus.Desc
+	collectorLastSuccess *prometheus.Desc
 	instanceLabel        string
 	collectors           []CollectorInstance
+	timeouts             map[string]time.Duration
+	defaultTimeout       time.Duration
+
+	refreshIntervals       map[string]time.Duration
+	defaultRefreshInterval time.Duration
+	statusMutex            sync.RWMutex
+	status                 map[string]collectorStatus
+	stopPolling            context.CancelFunc
+	polling                sync.WaitGroup
+
+	pluginDetectionInterval time.Duration
+	explicitCollectors      map[string]bool
+	pluginsMutex            sync.RWMutex
+	autoDisabledCollectors  map[string]pluginRequirement
+	autoDisabledInfo        *prometheus.Desc
 }
 
 type Option func(*Collector) error
@@ -74,6 +140,36 @@ func withoutCollectorInstance(name string) Option {
 	}
 }
 
+// WithoutCollector Option
+// removes the collector with the given name from the list of collectors
+func WithoutCollector(name string) Option {
+	return withoutCollectorInstance(name)
+}
+
+// WithCollectorTimeout Option
+// bounds every update of the collector with the given name by the timeout.
+// The collector is reported as failed when it misses the timeout.
+func WithCollectorTimeout(name string, timeout time.Duration) Option {
+	return func(o *Collector) error {
+		for _, collector := range o.collectors {
+			if collector.Name() == name {
+				o.timeouts[name] = timeout
+				return nil
+			}
+		}
+		return fmt.Errorf("collector %s not found", name)
+	}
+}
+
+// WithDefaultCollectorTimeout Option
+// bounds every update of the collectors without their own timeout by the timeout.
+func WithDefaultCollectorTimeout(timeout time.Duration) Option {
+	return func(o *Collector) error {
+		o.defaultTimeout = timeout
+		return nil
+	}
+}
+
 // WithoutArpTableCollector Option
 // removes the arp_table collector from the list of collectors
 func WithoutArpTableCollector() Option {
@@ -123,10 +219,20 @@ func WithoutOpenVPNCollector() Option {
 // New creates a new Collector instance.
 func New(client *opnsense.Client, log *slog.Logger, instanceName string, options ...Option) (*Collector, error) {
 	c := Collector{
-		Client:        client,
-		log:           log,
-		instanceLabel: instanceName,
-		collectors:    collectorInstances,
+		Client:           client,
+		log:              log,
+		instanceLabel:    instanceName,
+		timeouts:         make(map[string]time.Duration),
+		refreshIntervals: make(map[string]time.Duration),
+		status:           make(map[string]collectorStatus),
+		updateMutexes:    make(map[string]*sync.Mutex),
+
+		explicitCollectors:     make(map[string]bool),
+		autoDisabledCollectors: make(map[string]pluginRequirement),
+	}
+
+	for _, factory := range collectorFactories {
+		c.collectors = append(c.collectors, factory())
 	}
 
 	for _, option := range options {
@@ -135,8 +241,15 @@ func New(client *opnsense.Client, log *slog.Logger, instanceName string, options
 		}
 	}
 
+	// the opt-in collectors only run when they are explicitly enabled
+	c.collectors = slices.DeleteFunc(c.collectors, func(collector CollectorInstance) bool {
+		_, optIn := collector.(optInCollector)
+		return optIn && !c.explicitCollectors[collector.Name()]
+	})
+
 	for _, collector := range c.collectors {
 		collector.Register(namespace, instanceName, c.log)
+		c.updateMutexes[collector.Name()] = &sync.Mutex{}
 	}
 
 	c.isUp = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -169,9 +282,36 @@ func New(client *opnsense.Client, log *slog.Logger, instanceName string, options
 		Help:      "Total number of errors by endpoint returned by the OPNsense API during data fetching",
 	}, []string{"endpoint", "opnsense_instance"})
 
-	for _, metric := range []prometheus.Collector{c.isUp, c.scrapes, c.endpointErrors} {
-		prometheus.MustRegister(metric)
-	}
+	c.apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "exporter_api_request_duration_seconds",
+		Help:      "Duration of the requests sent to the OPNsense API by endpoint",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 15},
+	}, []string{"endpoint", "opnsense_instance"})
+
+	c.collectorDuration = buildPrometheusDesc("exporter", "collector_duration_seconds",
+		"Duration of the last update of a collector",
+		[]string{"collector"},
+	)
+
+	c.collectorSuccess = buildPrometheusDesc("exporter", "collector_success",
+		"Whether the last update of a collector was successful (1 = yes, 0 = no)",
+		[]string{"collector"},
+	)
+
+	c.collectorLastSuccess = buildPrometheusDesc("exporter", "collector_last_success_timestamp_seconds",
+		"Timestamp of the last successful update of a collector",
+		[]string{"collector"},
+	)
+
+	c.autoDisabledInfo = buildPrometheusDesc("exporter", "collector_auto_disabled_info",
+		"Collectors that are disabled because their OPNsense plugin is not installed or their API needs a newer OPNsense release",
+		[]string{"collector", "plugin", "min_version"},
+	)
+
+	c.Client.SetRequestDurationObserver(
+		c.apiRequestDuration.MustCurryWith(prometheus.Labels{instanceLabelName: instanceName}),
+	)
 
 	c.scrapes.WithLabelValues(c.instanceLabel).Add(0)
 
@@ -181,19 +321,161 @@ func New(client *opnsense.Client, log *slog.Logger, instanceName string, options
 	return &c, nil
 }
 
+// NewFromTarget builds the OPNsense client for the target
+// and a Collector without the collectors that are disabled in the target config.
+// The background refresh of the collectors is already started;
+// Stop must be called when the Collector is no longer used.
+func NewFromTarget(target options.TargetConfig, version string, log *slog.Logger) (*Collector, error) {
+	c, err := BuildFromTarget(target, version, log)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Start()
+	return c, nil
+}
+
+// BuildFromTarget builds the Collector of the target like NewFromTarget,
+// without starting the background refresh of the collectors.
+func BuildFromTarget(target options.TargetConfig, version string, log *slog.Logger) (*Collector, error) {
+	client, err := opnsense.NewClient(target.OPNSenseConfig, version, log)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("opnsense client build failed"), err)
+	}
+
+	log.Debug(fmt.Sprintf("OPNsense registered endpoints %s", client.Endpoints()))
+
+	collectorOptionFuncs := []Option{}
+	for _, name := range target.DisabledCollectors() {
+		collectorOptionFuncs = append(collectorOptionFuncs, WithoutCollector(name))
+		log.Info("collector disabled", "collector", name)
+	}
+	for _, name := range target.EnabledCollectors() {
+		collectorOptionFuncs = append(collectorOptionFuncs, WithExplicitCollector(name))
+	}
+	collectorOptionFuncs = append(collectorOptionFuncs, WithPluginDetection(target.PluginDetectionInterval))
+	collectorOptionFuncs = append(collectorOptionFuncs, WithDefaultCollectorTimeout(target.CollectorTimeout))
+	for name, timeout := range target.CollectorTimeouts() {
+		collectorOptionFuncs = append(collectorOptionFuncs, WithCollectorTimeout(name, timeout))
+	}
+	collectorOptionFuncs = append(collectorOptionFuncs, WithDefaultRefreshInterval(target.RefreshInterval))
+	for name, interval := range target.CollectorRefreshIntervals() {
+		collectorOptionFuncs = append(collectorOptionFuncs, WithRefreshInterval(name, interval))
+	}
+	for name, collector := range target.Collectors {
+		collectorOptionFuncs = append(collectorOptionFuncs, WithCollectorSettings(name, Settings{
+			Details: collector.Details,
+			Pools:   collector.Pools,
+			TopN:    collector.TopN,
+			Include: collector.Include,
+			Exclude: collector.Exclude,
+		}))
+	}
+
+	return New(&client, log, target.InstanceLabel, collectorOptionFuncs...)
+}
+
+// update runs a single update of the collector bounded by its timeout
+// and records the result in the status of the collector.
+func (c *Collector) update(ctx context.Context, coll CollectorInstance, ch chan<- prometheus.Metric) collectorStatus {
+	updateMutex := c.updateMutexes[coll.Name()]
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	start := time.Now()
+
+	timeout, ok := c.timeouts[coll.Name()]
+	if !ok {
+		timeout = c.defaultTimeout
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := coll.Update(ctx, c.Client, ch)
+	if err != nil {
+		c.log.Error(
+			"failed to update",
+			"component", "collector",
+			"collector_name", coll.Name(),
+			"err", err,
+		)
+		c.endpointErrors.WithLabelValues(err.Endpoint, c.instanceLabel).Inc()
+	}
+
+	c.statusMutex.Lock()
+	defer c.statusMutex.Unlock()
+
+	status := c.status[coll.Name()]
+	status.duration = time.Since(start)
+	status.success = err == nil
+	if status.success {
+		status.lastSuccess = time.Now()
+	}
+	c.status[coll.Name()] = status
+
+	return status
+}
+
+// collectStatus sends the duration, success and last success metrics of a collector.
+func (c *Collector) collectStatus(ch chan<- prometheus.Metric, name string, status collectorStatus) {
+	success := 0.0
+	if status.success {
+		success = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.collectorDuration,
+		prometheus.GaugeValue,
+		status.duration.Seconds(),
+		name,
+		c.instanceLabel,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.collectorSuccess,
+		prometheus.GaugeValue,
+		success,
+		name,
+		c.instanceLabel,
+	)
+
+	if !status.lastSuccess.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			c.collectorLastSuccess,
+			prometheus.GaugeValue,
+			float64(status.lastSuccess.UnixNano())/1e9,
+			name,
+			c.instanceLabel,
+		)
+	}
+}
+
 // Describe implements the prometheus.Collector interface.
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.scrapes.Describe(ch)
 	c.endpointErrors.Describe(ch)
+	c.apiRequestDuration.Describe(ch)
 	c.isUp.Describe(ch)
+	c.firewallHealthStatus.Describe(ch)
+	ch <- c.collectorDuration
+	ch <- c.collectorSuccess
+	ch <- c.collectorLastSuccess
+	ch <- c.autoDisabledInfo
 
 	for _, collector := range c.collectors {
 		collector.Describe(ch)
 	}
 }
 
-func (c *Collector) collectHealthMetrics(ch chan<- prometheus.Metric) error {
-	systemStatus, err := c.Client.HealthCheck()
+func (c *Collector) collectHealthMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	systemStatus, err := c.Client.HealthCheck(ctx)
+
+	c.healthMutex.Lock()
+	defer c.healthMutex.Unlock()
+
 	if err != nil {
 		c.isUp.Set(0)
 		c.isUp.Collect(ch)
@@ -221,11 +503,17 @@ func (c *Collector) collectHealthMetrics(ch chan<- prometheus.Metric) error {
 }
 
 // Collect implements the prometheus.Collector interface.
+// The collection is not bounded by a scrape timeout, use WithContext for that.
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	c.collect(context.Background(), nil, ch)
+}
 
-	if err := c.collectHealthMetrics(ch); err != nil {
+// collect fetches the metrics of all collectors. The updates are aborted
+// when the context is done and the affected collectors are reported as failed.
+// Collectors with a refresh interval are served from their cached snapshot.
+// A non-empty filter limits the collection to the collectors in the filter.
+func (c *Collector) collect(ctx context.Context, filter map[string]bool, ch chan<- prometheus.Metric) {
+	if err := c.collectHealthMetrics(ctx, ch); err != nil {
 		c.log.Error(
 			"failed to fetch system health status; skipping other metrics",
 			"err", err,
@@ -233,25 +521,44 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	var wg sync.WaitGroup
-	wg.Add(len(c.collectors))
 
 	for _, collector := range c.collectors {
+		if len(filter) > 0 && !filter[collector.Name()] {
+			continue
+		}
+		if c.autoDisabled(collector.Name()) {
+			continue
+		}
+		if c.refreshInterval(collector.Name()) > 0 {
+			c.collectCached(ch, collector.Name())
+			continue
+		}
+
+		wg.Add(1)
 		go func(coll CollectorInstance) {
-			if err := coll.Update(c.Client, ch); err != nil {
-				c.log.Error(
-					"failed to update",
-					"component", "collector",
-					"collector_name", coll.Name(),
-					"err", err,
-				)
-				c.endpointErrors.WithLabelValues(err.Endpoint, c.instanceLabel).Inc()
-			}
+			status := c.update(ctx, coll, ch)
+			c.collectStatus(ch, coll.Name(), status)
 			wg.Done()
 		}(collector)
 	}
 	wg.Wait()
 
+	c.pluginsMutex.RLock()
+	for name, requirement := range c.autoDisabledCollectors {
+		ch <- prometheus.MustNewConstMetric(
+			c.autoDisabledInfo,
+			prometheus.GaugeValue,
+			1,
+			name,
+			requirement.plugin,
+			requirement.since,
+			c.instanceLabel,
+		)
+	}
+	c.pluginsMutex.RUnlock()
+
 	c.scrapes.WithLabelValues(c.instanceLabel).Inc()
 	c.scrapes.Collect(ch)
 	c.endpointErrors.Collect(ch)
+	c.apiRequestDuration.Collect(ch)
 }
@@ -1,13 +1,34 @@
 package collector
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
 	"github.com/AthennaMind/opnsense-exporter/internal/options"
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/promslog"
 )
 
+// updateCollector runs a single update of a collector on every Collect,
+// so the metrics of the collector can be checked with testutil.
+type updateCollector struct {
+	CollectorInstance
+	client *opnsense.Client
+	err    *opnsense.APICallError
+}
+
+func newUpdateCollector(coll CollectorInstance, client *opnsense.Client) *updateCollector {
+	coll.Register(namespace, "test", promslog.NewNopLogger())
+	return &updateCollector{CollectorInstance: coll, client: client}
+}
+
+func (u *updateCollector) Collect(ch chan<- prometheus.Metric) {
+	u.err = u.Update(context.Background(), u.client, ch)
+}
+
 func TestCollector(t *testing.T) {
 	conf := options.OPNSenseConfig{
 		Protocol: "http",
@@ -48,6 +69,87 @@ func TestCollector(t *testing.T) {
 			t.Errorf("expected wireguard collector to be removed")
 		case "firewall":
 			t.Errorf("expected firewall collector to be removed")
+		case "firewall_log":
+			t.Errorf("expected the opt-in firewall_log collector to be disabled by default")
+		}
+	}
+
+	if Defaults()[FirewallLogSubsystem] {
+		t.Errorf("expected the firewall_log collector to be disabled by default")
+	}
+
+	collector, err = New(&client, promslog.NewNopLogger(), "test", WithExplicitCollector(FirewallLogSubsystem))
+	if err != nil {
+		t.Errorf("expected no error when creating collector, got %v", err)
+	}
+	enabled := false
+	for _, c := range collector.collectors {
+		if c.Name() == FirewallLogSubsystem {
+			enabled = true
+		}
+	}
+	if !enabled {
+		t.Errorf("expected the explicitly enabled firewall_log collector to be registered")
+	}
+}
+
+func TestCollectorMetrics(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	server.SetFault("arp", opnsensetest.Fault{StatusCode: http.StatusInternalServerError})
+	client := server.Client(t)
+
+	collector, err := New(client, promslog.NewNopLogger(), "test")
+	if err != nil {
+		t.Fatalf("expected no error when creating collector, got %v", err)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("expected no error when gathering, got %v", err)
+	}
+
+	success := make(map[string]float64)
+	durations := make(map[string]bool)
+	requests := make(map[string]uint64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			switch family.GetName() {
+			case "opnsense_exporter_collector_success":
+				success[labels["collector"]] = metric.GetGauge().GetValue()
+			case "opnsense_exporter_collector_duration_seconds":
+				durations[labels["collector"]] = true
+			case "opnsense_exporter_api_request_duration_seconds":
+				requests[labels["endpoint"]] = metric.GetHistogram().GetSampleCount()
+			}
 		}
 	}
+
+	if got, ok := success[ArpTableSubsystem]; !ok || got != 0 {
+		t.Errorf("expected the failed arp_table collector to report success 0, got %v", got)
+	}
+	if got, ok := success[InterfacesSubsystem]; !ok || got != 1 {
+		t.Errorf("expected the interfaces collector to report success 1, got %v", got)
+	}
+	for _, name := range []string{ArpTableSubsystem, InterfacesSubsystem} {
+		if !durations[name] {
+			t.Errorf("expected the duration of the %s collector", name)
+		}
+	}
+
+	endpoints := client.Endpoints()
+	for _, name := range []opnsense.EndpointName{"arp", "interfaces", "systemTime"} {
+		path := string(endpoints[name])
+		if requests[path] == 0 {
+			t.Errorf("expected observations of the request duration of %s, got none", path)
+		}
+	}
+	if got, expected := requests[string(endpoints["interfaces"])], uint64(server.Requests("interfaces")); got != expected {
+		t.Errorf("expected an observation per request of %s, got %d observations of %d requests", endpoints["interfaces"], got, expected)
+	}
 }
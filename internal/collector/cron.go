@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -16,8 +17,10 @@ type cronCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &cronCollector{
-		subsystem: CronTableSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &cronCollector{
+			subsystem: CronTableSubsystem,
+		}
 	})
 }
 
@@ -40,8 +43,12 @@ func (c *cronCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.jobsStatus
 }
 
-func (c *cronCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	crons, err := client.FetchCronTable()
+func (c *cronCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"cronJobs"}
+}
+
+func (c *cronCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	crons, err := client.FetchCronTable(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,298 @@
+package collector
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// leaseMetrics holds the lease and pool metrics that are shared by the collectors
+// of all DHCP servers. The backend label tells the DHCP servers apart,
+// so dashboards keep working when moving from one DHCP server to another.
+type leaseMetrics struct {
+	leases          *prometheus.Desc
+	leaseInfo       *prometheus.Desc
+	poolSize        *prometheus.Desc
+	poolUsed        *prometheus.Desc
+	poolUtilization *prometheus.Desc
+
+	details bool
+	pools   []leasePool
+}
+
+// leasePool is an address range of a DHCP server on an interface.
+type leasePool struct {
+	iface       string
+	name        string
+	first, last netip.Addr
+}
+
+// register builds the descriptors of the metrics within the subsystem (dhcpv4 or dhcpv6).
+func (m *leaseMetrics) register(subsystem string) {
+	m.leases = buildPrometheusDesc(subsystem, "leases",
+		"Number of DHCP leases by backend, interface, type (dynamic, static) and state",
+		[]string{"backend", "interface", "type", "state"},
+	)
+	m.leaseInfo = buildPrometheusDesc(subsystem, "lease_info",
+		"DHCP lease by backend, interface, address, mac, hostname, type and state",
+		[]string{"backend", "interface", "address", "mac", "hostname", "type", "state"},
+	)
+	m.poolSize = buildPrometheusDesc(subsystem, "pool_size_addresses",
+		"Number of addresses in the DHCP pool",
+		[]string{"backend", "interface", "pool"},
+	)
+	m.poolUsed = buildPrometheusDesc(subsystem, "pool_used_addresses",
+		"Number of addresses in the DHCP pool with an active dynamic lease",
+		[]string{"backend", "interface", "pool"},
+	)
+	m.poolUtilization = buildPrometheusDesc(subsystem, "pool_utilization_ratio",
+		"Ratio of the addresses in the DHCP pool with an active dynamic lease (0-1)",
+		[]string{"backend", "interface", "pool"},
+	)
+}
+
+// configure applies the lease details switch and the configured pools.
+func (m *leaseMetrics) configure(settings Settings) error {
+	m.details = settings.Details
+	m.pools = nil
+
+	for iface, poolRange := range settings.Pools {
+		pool, err := parseLeasePool(iface, poolRange)
+		if err != nil {
+			return err
+		}
+		m.pools = append(m.pools, pool)
+	}
+	sort.Slice(m.pools, func(i, j int) bool {
+		return m.pools[i].iface < m.pools[j].iface
+	})
+
+	return nil
+}
+
+func (m *leaseMetrics) describe(ch chan<- *prometheus.Desc) {
+	ch <- m.leases
+	ch <- m.leaseInfo
+	ch <- m.poolSize
+	ch <- m.poolUsed
+	ch <- m.poolUtilization
+}
+
+// parseLeasePool parses a pool range in the form "first-last" or a prefix like "192.168.1.128/25".
+func parseLeasePool(iface, poolRange string) (leasePool, error) {
+	if prefix, err := netip.ParsePrefix(strings.TrimSpace(poolRange)); err == nil {
+		prefix = prefix.Masked()
+		first := prefix.Addr()
+		last := first.AsSlice()
+		for i := prefix.Bits(); i < len(last)*8; i++ {
+			last[i/8] |= 1 << (7 - i%8)
+		}
+		lastAddr, _ := netip.AddrFromSlice(last)
+		return leasePool{
+			iface: iface,
+			name:  prefix.String(),
+			first: first,
+			last:  lastAddr,
+		}, nil
+	}
+
+	firstStr, lastStr, ok := strings.Cut(poolRange, "-")
+	if !ok {
+		return leasePool{}, fmt.Errorf("pool %q of interface %s is not in the form first-last", poolRange, iface)
+	}
+
+	first, err := netip.ParseAddr(strings.TrimSpace(firstStr))
+	if err != nil {
+		return leasePool{}, fmt.Errorf("invalid first address of pool %q of interface %s: %w", poolRange, iface, err)
+	}
+	last, err := netip.ParseAddr(strings.TrimSpace(lastStr))
+	if err != nil {
+		return leasePool{}, fmt.Errorf("invalid last address of pool %q of interface %s: %w", poolRange, iface, err)
+	}
+	if first.Is4() != last.Is4() || last.Less(first) {
+		return leasePool{}, fmt.Errorf("pool %q of interface %s is not a valid address range", poolRange, iface)
+	}
+
+	return leasePool{
+		iface: iface,
+		name:  first.String() + "-" + last.String(),
+		first: first,
+		last:  last,
+	}, nil
+}
+
+// size returns the number of addresses in the pool.
+func (p leasePool) size() float64 {
+	first, last := p.first.As16(), p.last.As16()
+	n := new(big.Int).Sub(new(big.Int).SetBytes(last[:]), new(big.Int).SetBytes(first[:]))
+	size, _ := new(big.Float).SetInt(n.Add(n, big.NewInt(1))).Float64()
+	return size
+}
+
+// contains reports whether the address is within the pool.
+func (p leasePool) contains(addr netip.Addr) bool {
+	return addr.Is4() == p.first.Is4() && !addr.Less(p.first) && !p.last.Less(addr)
+}
+
+// mergePools returns the pools reported by the backend and the configured pools
+// the backend does not report. Pools with the same interface and addresses are only
+// returned once, as they would otherwise be exposed as duplicate series.
+func (m *leaseMetrics) mergePools(reported []opnsense.DHCPPool) []leasePool {
+	type poolKey struct {
+		iface       string
+		first, last netip.Addr
+	}
+	seen := make(map[poolKey]bool)
+
+	var pools []leasePool
+	add := func(pool leasePool) {
+		key := poolKey{pool.iface, pool.first, pool.last}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		pools = append(pools, pool)
+	}
+
+	for _, pool := range reported {
+		p, err := parseLeasePool(pool.Interface, pool.Range)
+		if err != nil {
+			continue
+		}
+		add(p)
+	}
+	for _, pool := range m.pools {
+		add(pool)
+	}
+	return pools
+}
+
+// collect sends the lease and pool metrics of the leases of the backend.
+// The configured pools are used in addition to the pools reported by the backend.
+func (m *leaseMetrics) collect(ch chan<- prometheus.Metric, instance, backend string, data opnsense.DHCPLeases) {
+	type leaseKey struct{ iface, leaseType, state string }
+	counts := make(map[leaseKey]int)
+
+	pools := m.mergePools(data.Pools)
+	used := make([]int, len(pools))
+
+	for _, lease := range uniqueLeases(data.Leases) {
+		leaseType := "dynamic"
+		if lease.Static {
+			leaseType = "static"
+		}
+		counts[leaseKey{lease.Interface, leaseType, lease.State}]++
+
+		if m.details {
+			ch <- prometheus.MustNewConstMetric(
+				m.leaseInfo,
+				prometheus.GaugeValue,
+				1,
+				backend,
+				lease.Interface,
+				lease.Address,
+				lease.Mac,
+				lease.Hostname,
+				leaseType,
+				lease.State,
+				instance,
+			)
+		}
+
+		if lease.Static || lease.State != "active" {
+			continue
+		}
+		addr, err := netip.ParseAddr(lease.Address)
+		if err != nil {
+			continue
+		}
+		for i, pool := range pools {
+			if pool.iface == lease.Interface && pool.contains(addr) {
+				used[i]++
+			}
+		}
+	}
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			m.leases,
+			prometheus.GaugeValue,
+			float64(count),
+			backend,
+			key.iface,
+			key.leaseType,
+			key.state,
+			instance,
+		)
+	}
+
+	for i, pool := range pools {
+		size := pool.size()
+		ch <- prometheus.MustNewConstMetric(
+			m.poolSize,
+			prometheus.GaugeValue,
+			size,
+			backend,
+			pool.iface,
+			pool.name,
+			instance,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			m.poolUsed,
+			prometheus.GaugeValue,
+			float64(used[i]),
+			backend,
+			pool.iface,
+			pool.name,
+			instance,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			m.poolUtilization,
+			prometheus.GaugeValue,
+			float64(used[i])/size,
+			backend,
+			pool.iface,
+			pool.name,
+			instance,
+		)
+	}
+}
+
+// uniqueLeases returns the leases without duplicates of the same lease. The ISC DHCP server
+// keeps the former states of a lease, so a lease can be reported more than once, e.g. as
+// expired and as active. Of the duplicates, the active lease is kept, otherwise the newest one.
+func uniqueLeases(leases []opnsense.DHCPLease) []opnsense.DHCPLease {
+	type leaseKey struct {
+		iface, address, mac, hostname string
+		static                        bool
+	}
+
+	unique := make([]opnsense.DHCPLease, 0, len(leases))
+	index := make(map[leaseKey]int, len(leases))
+	for _, lease := range leases {
+		key := leaseKey{lease.Interface, lease.Address, lease.Mac, lease.Hostname, lease.Static}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(unique)
+			unique = append(unique, lease)
+			continue
+		}
+
+		kept := unique[i]
+		if (kept.State == "active") != (lease.State == "active") {
+			if lease.State == "active" {
+				unique[i] = lease
+			}
+			continue
+		}
+		if !lease.Ends.Before(kept.Ends) {
+			unique[i] = lease
+		}
+	}
+	return unique
+}
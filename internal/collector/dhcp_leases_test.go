@@ -0,0 +1,135 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// leaseMetricsCollector collects the lease metrics of fixed leases.
+type leaseMetricsCollector struct {
+	metrics *leaseMetrics
+	data    opnsense.DHCPLeases
+}
+
+func (c *leaseMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *leaseMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.collect(ch, "test", "kea", c.data)
+}
+
+func TestParseLeasePool(t *testing.T) {
+	tests := []struct {
+		name      string
+		poolRange string
+		size      float64
+		expectErr bool
+	}{
+		{name: "IPv4", poolRange: "192.168.1.100-192.168.1.199", size: 100},
+		{name: "IPv4 with spaces", poolRange: "10.0.0.1 - 10.0.1.0", size: 256},
+		{name: "Single address", poolRange: "10.0.0.1-10.0.0.1", size: 1},
+		{name: "IPv4 prefix", poolRange: "192.168.1.128/25", size: 128},
+		{name: "Unmasked prefix", poolRange: "192.168.1.130/30", size: 4},
+		{name: "IPv6 prefix", poolRange: "2001:db8::/116", size: 4096},
+		{name: "IPv6", poolRange: "2001:db8::1000-2001:db8::1fff", size: 4096},
+		{name: "Missing separator", poolRange: "192.168.1.100", expectErr: true},
+		{name: "Invalid address", poolRange: "192.168.1.100-192.168.1.300", expectErr: true},
+		{name: "Reversed", poolRange: "192.168.1.199-192.168.1.100", expectErr: true},
+		{name: "Mixed families", poolRange: "192.168.1.100-2001:db8::1", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pool, err := parseLeasePool("LAN", tc.poolRange)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected an error for %q", tc.poolRange)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := pool.size(); got != tc.size {
+				t.Errorf("size of %q = %v, expected %v", tc.poolRange, got, tc.size)
+			}
+		})
+	}
+}
+
+func TestUniqueLeases(t *testing.T) {
+	older := time.Date(2024, 7, 31, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(2 * time.Hour)
+	lease := func(state string, ends time.Time) opnsense.DHCPLease {
+		return opnsense.DHCPLease{Address: "192.168.1.100", Mac: "3c:22:fb:11:0a:7e", Hostname: "macbook", Interface: "LAN", State: state, Ends: ends}
+	}
+
+	tests := []struct {
+		name     string
+		leases   []opnsense.DHCPLease
+		expected opnsense.DHCPLease
+	}{
+		{name: "Active after expired", leases: []opnsense.DHCPLease{lease("expired", newer), lease("active", older)}, expected: lease("active", older)},
+		{name: "Active before expired", leases: []opnsense.DHCPLease{lease("active", older), lease("expired", newer)}, expected: lease("active", older)},
+		{name: "Newest expired", leases: []opnsense.DHCPLease{lease("expired", newer), lease("expired", older)}, expected: lease("expired", newer)},
+		{name: "Without end", leases: []opnsense.DHCPLease{lease("expired", time.Time{}), lease("free", time.Time{})}, expected: lease("free", time.Time{})},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := uniqueLeases(tc.leases)
+			if len(got) != 1 {
+				t.Fatalf("expected a single lease, got %d", len(got))
+			}
+			if got[0] != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, got[0])
+			}
+		})
+	}
+}
+
+func TestLeaseMetricsOverlappingPools(t *testing.T) {
+	metrics := &leaseMetrics{}
+	metrics.register(DHCPv4Subsystem)
+	err := metrics.configure(Settings{Pools: map[string]string{
+		"LAN": "192.168.1.100-192.168.1.199",
+		"OPT": "10.0.0.0/30",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the backend reports the configured pools, the OPT pool as a range instead of a prefix
+	c := &leaseMetricsCollector{metrics: metrics, data: opnsense.DHCPLeases{
+		Leases: []opnsense.DHCPLease{
+			{Address: "192.168.1.100", Mac: "3c:22:fb:11:0a:7e", Hostname: "macbook", Interface: "LAN", State: "active"},
+		},
+		Pools: []opnsense.DHCPPool{
+			{Interface: "LAN", Range: "192.168.1.100 - 192.168.1.199"},
+			{Interface: "LAN", Range: "192.168.1.100-192.168.1.199"},
+			{Interface: "OPT", Range: "10.0.0.0-10.0.0.3"},
+		},
+	}}
+
+	expected := `
+# HELP opnsense_dhcpv4_pool_size_addresses Number of addresses in the DHCP pool
+# TYPE opnsense_dhcpv4_pool_size_addresses gauge
+opnsense_dhcpv4_pool_size_addresses{backend="kea",interface="LAN",opnsense_instance="test",pool="192.168.1.100-192.168.1.199"} 100
+opnsense_dhcpv4_pool_size_addresses{backend="kea",interface="OPT",opnsense_instance="test",pool="10.0.0.0-10.0.0.3"} 4
+# HELP opnsense_dhcpv4_pool_used_addresses Number of addresses in the DHCP pool with an active dynamic lease
+# TYPE opnsense_dhcpv4_pool_used_addresses gauge
+opnsense_dhcpv4_pool_used_addresses{backend="kea",interface="LAN",opnsense_instance="test",pool="192.168.1.100-192.168.1.199"} 1
+opnsense_dhcpv4_pool_used_addresses{backend="kea",interface="OPT",opnsense_instance="test",pool="10.0.0.0-10.0.0.3"} 0
+`
+	err = testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"opnsense_dhcpv4_pool_size_addresses", "opnsense_dhcpv4_pool_used_addresses")
+	if err != nil {
+		t.Error(err)
+	}
+}
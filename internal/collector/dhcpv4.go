@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type dhcpv4Collector struct {
+	leaseMetrics
+
+	log       *slog.Logger
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &dhcpv4Collector{
+			subsystem: DHCPv4Subsystem,
+		}
+	})
+}
+
+func (c *dhcpv4Collector) Name() string {
+	return c.subsystem
+}
+
+// Configure applies the lease details switch and the pools of the ISC DHCPv4 server,
+// which can not be fetched from its API.
+func (c *dhcpv4Collector) Configure(settings Settings) error {
+	return c.configure(settings)
+}
+
+func (c *dhcpv4Collector) Register(namespace, instance string, log *slog.Logger) {
+	c.log = log
+	c.instance = instance
+
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.register(c.subsystem)
+}
+
+func (c *dhcpv4Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.describe(ch)
+}
+
+func (c *dhcpv4Collector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"dhcpv4"}
+}
+
+func (c *dhcpv4Collector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchDHCPv4Leases(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.collect(ch, c.instance, "isc", data)
+
+	return nil
+}
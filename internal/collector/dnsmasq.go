@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dnsmasqCollector exposes the DHCP leases and ranges of Dnsmasq
+// with the same metrics as the dhcpv4 collector and backend="dnsmasq".
+type dnsmasqCollector struct {
+	v4 leaseMetrics
+	v6 leaseMetrics
+
+	log       *slog.Logger
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &dnsmasqCollector{
+			subsystem: DnsmasqSubsystem,
+		}
+	})
+}
+
+func (c *dnsmasqCollector) Name() string {
+	return c.subsystem
+}
+
+// Configure applies the lease details switch.
+// The DHCP ranges of Dnsmasq are fetched from its API, so configured pools are ignored.
+func (c *dnsmasqCollector) Configure(settings Settings) error {
+	c.v4.details = settings.Details
+	c.v6.details = settings.Details
+	return nil
+}
+
+func (c *dnsmasqCollector) Register(namespace, instance string, log *slog.Logger) {
+	c.log = log
+	c.instance = instance
+
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.v4.register(DHCPv4Subsystem)
+	c.v6.register(DHCPv6Subsystem)
+}
+
+func (c *dnsmasqCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.v4.describe(ch)
+	c.v6.describe(ch)
+}
+
+func (c *dnsmasqCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"dnsmasqLeases", "dnsmasqRanges"}
+}
+
+func (c *dnsmasqCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchDnsmasqLeases(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.v4.collect(ch, c.instance, "dnsmasq", data.V4)
+	c.v6.collect(ch, c.instance, "dnsmasq", data.V6)
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+)
+
+// exporterEndpoints holds the OPNsense API endpoints the exporter itself calls
+// outside of the collectors, with the feature that depends on them.
+var exporterEndpoints = map[opnsense.EndpointName]string{
+	"healthCheck":  "up",
+	"firmwareInfo": "plugin detection",
+}
+
+// EndpointDependents returns the names of the collectors and exporter
+// features that depend on the OPNsense API endpoint.
+func EndpointDependents(endpoint opnsense.EndpointName) []string {
+	var dependents []string
+	if feature, ok := exporterEndpoints[endpoint]; ok {
+		dependents = append(dependents, feature)
+	}
+	for _, factory := range collectorFactories {
+		collector := factory()
+		for _, e := range collector.Endpoints() {
+			if e == endpoint {
+				dependents = append(dependents, collector.Name())
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// Endpoints returns the OPNsense API endpoints the Collector calls:
+// the endpoints of the exporter and of the enabled collectors.
+// When plugin detection is enabled, the plugins are detected first
+// and the endpoints of the automatically disabled collectors are left out.
+func (c *Collector) Endpoints(ctx context.Context) []opnsense.EndpointName {
+	endpoints := []opnsense.EndpointName{"healthCheck"}
+	if c.pluginDetectionInterval > 0 {
+		endpoints = append(endpoints, "firmwareInfo")
+		c.detectPlugins(ctx)
+	}
+
+	for _, collector := range c.collectors {
+		if c.autoDisabled(collector.Name()) {
+			continue
+		}
+		endpoints = append(endpoints, collector.Endpoints()...)
+	}
+	return endpoints
+}
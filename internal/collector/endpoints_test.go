@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+)
+
+func TestEndpointsCoverClient(t *testing.T) {
+	client := opnsensetest.NewServer(t, "25.7").Client(t)
+	clientEndpoints := client.Endpoints()
+
+	covered := make(map[opnsense.EndpointName]bool)
+	for endpoint := range exporterEndpoints {
+		covered[endpoint] = true
+	}
+	for _, factory := range collectorFactories {
+		collector := factory()
+		for _, endpoint := range collector.Endpoints() {
+			if _, ok := clientEndpoints[endpoint]; !ok {
+				t.Errorf("collector %s declares the endpoint %s that the client does not know", collector.Name(), endpoint)
+			}
+			covered[endpoint] = true
+		}
+	}
+
+	for endpoint := range clientEndpoints {
+		if !covered[endpoint] {
+			t.Errorf("endpoint %s is not declared by any collector or by the exporter", endpoint)
+		}
+	}
+}
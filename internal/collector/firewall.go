@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -24,8 +25,10 @@ type firewallCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &firewallCollector{
-		subsystem: FirewallSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &firewallCollector{
+			subsystem: FirewallSubsystem,
+		}
 	})
 }
 
@@ -91,8 +94,12 @@ func (c *firewallCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.outIPv6BlockPackets
 }
 
-func (c *firewallCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchPFStatsByInterface()
+func (c *firewallCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"pfStatisticsByInterface"}
+}
+
+func (c *firewallCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchPFStatsByInterface(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,231 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// firewallLogLimit is the maximum number of log entries fetched by an update.
+	firewallLogLimit = 1000
+	// firewallLogSeenDigests is the number of digests of the last counted
+	// log entries that are kept to skip the entries that were already counted.
+	firewallLogSeenDigests = 2 * firewallLogLimit
+	// topCounterFactor is the number of keys tracked by the top lists per exposed entry.
+	topCounterFactor = 10
+)
+
+// firewallLogKey holds the labels of the aggregated log entries.
+type firewallLogKey struct {
+	iface     string
+	action    string
+	direction string
+	protocol  string
+	label     string
+}
+
+type firewallLogCollector struct {
+	log *slog.Logger
+
+	events          *prometheus.Desc
+	truncatedPolls  *prometheus.Desc
+	topSources      *prometheus.Desc
+	topDestinations *prometheus.Desc
+
+	// mutex serializes the updates, as every update continues from the state of the previous one
+	mutex       sync.Mutex
+	initialized bool
+	digest      string
+	seen        map[string]struct{}
+	seenOrder   []string
+	counts      map[firewallLogKey]float64
+	truncated   float64
+	topN        int
+	sources     *topCounter
+	ports       *topCounter
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &firewallLogCollector{
+			subsystem: FirewallLogSubsystem,
+			seen:      make(map[string]struct{}),
+			counts:    make(map[firewallLogKey]float64),
+		}
+	})
+}
+
+func (c *firewallLogCollector) optIn() {}
+
+func (c *firewallLogCollector) Name() string {
+	return c.subsystem
+}
+
+// Configure sets the number of the exposed top sources and destination ports of the blocked traffic.
+func (c *firewallLogCollector) Configure(settings Settings) error {
+	if settings.TopN < 0 || settings.TopN > maxTopN {
+		return fmt.Errorf("top_n must be between 0 and %d, got %d", maxTopN, settings.TopN)
+	}
+	c.topN = settings.TopN
+	if c.topN > 0 {
+		c.sources = newTopCounter(c.topN * topCounterFactor)
+		c.ports = newTopCounter(c.topN * topCounterFactor)
+	}
+	return nil
+}
+
+func (c *firewallLogCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.events = buildPrometheusDesc(c.subsystem, "events_total",
+		"Total number of firewall log entries since the exporter started",
+		[]string{"interface", "action", "direction", "protocol", "label"})
+	c.truncatedPolls = buildPrometheusDesc(c.subsystem, "truncated_polls_total",
+		"Total number of polls of the firewall log that reached the entry limit and may have missed entries", nil)
+	// the top lists are gauges, as a key that was replaced in the top counter
+	// starts again from the count of the replaced key when it comes back
+	c.topSources = buildPrometheusDesc(c.subsystem, "top_blocked_source_events",
+		"Approximate number of blocked firewall log entries of the most blocked source addresses since the exporter started",
+		[]string{"source"})
+	c.topDestinations = buildPrometheusDesc(c.subsystem, "top_blocked_destination_port_events",
+		"Approximate number of blocked firewall log entries of the most blocked destination ports since the exporter started",
+		[]string{"protocol", "port"})
+}
+
+func (c *firewallLogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.events
+	ch <- c.truncatedPolls
+	ch <- c.topSources
+	ch <- c.topDestinations
+}
+
+func (c *firewallLogCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"firewallLog"}
+}
+
+func (c *firewallLogCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries, err := client.FetchFirewallLog(ctx, c.digest, firewallLogLimit)
+	if err != nil {
+		return err
+	}
+
+	if c.digest != "" && len(entries) >= firewallLogLimit {
+		c.truncated++
+		c.log.Warn("the firewall log has more new entries than are fetched by an update; lower the refresh interval of the collector",
+			"collector", c.Name(), "limit", firewallLogLimit)
+	}
+	c.aggregate(entries)
+
+	for key, count := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.events, prometheus.CounterValue, count,
+			key.iface, key.action, key.direction, key.protocol, key.label, c.instance)
+	}
+	ch <- prometheus.MustNewConstMetric(c.truncatedPolls, prometheus.CounterValue, c.truncated, c.instance)
+
+	if c.topN > 0 {
+		for _, source := range topEntries(c.sources.counts, c.topN) {
+			ch <- prometheus.MustNewConstMetric(c.topSources, prometheus.GaugeValue,
+				float64(c.sources.counts[source]), source, c.instance)
+		}
+		for _, port := range topEntries(c.ports.counts, c.topN) {
+			protocol, number, _ := strings.Cut(port, "/")
+			ch <- prometheus.MustNewConstMetric(c.topDestinations, prometheus.GaugeValue,
+				float64(c.ports.counts[port]), protocol, number, c.instance)
+		}
+	}
+
+	return nil
+}
+
+// aggregate counts the log entries, newest first, that were not counted before.
+// The entries of the first update are only marked as seen, so the counters
+// start with the exporter instead of with the history of the log.
+func (c *firewallLogCollector) aggregate(entries []opnsense.FirewallLogEntry) {
+	if len(entries) > 0 {
+		c.digest = entries[0].Digest
+	}
+
+	for _, entry := range entries {
+		if _, ok := c.seen[entry.Digest]; ok {
+			continue
+		}
+		c.markSeen(entry.Digest)
+
+		if !c.initialized {
+			continue
+		}
+
+		c.counts[firewallLogKey{
+			iface:     entry.Interface,
+			action:    entry.Action,
+			direction: entry.Direction,
+			protocol:  entry.Protocol,
+			label:     entry.Label,
+		}]++
+
+		if c.topN > 0 && entry.Action == "block" {
+			c.sources.add(entry.Source)
+			if entry.DestPort != "" {
+				c.ports.add(entry.Protocol + "/" + entry.DestPort)
+			}
+		}
+	}
+
+	c.initialized = true
+}
+
+// markSeen remembers the digest of a counted log entry.
+// Only the last firewallLogSeenDigests digests are kept.
+func (c *firewallLogCollector) markSeen(digest string) {
+	if len(c.seenOrder) >= firewallLogSeenDigests {
+		delete(c.seen, c.seenOrder[0])
+		c.seenOrder = c.seenOrder[1:]
+	}
+	c.seen[digest] = struct{}{}
+	c.seenOrder = append(c.seenOrder, digest)
+}
+
+// topCounter counts the most frequent keys in bounded memory with the Space-Saving algorithm:
+// when it is full, the key with the lowest count is replaced by the new key, which takes over
+// the count of the replaced key. The count of a key is overestimated by at most that lowest count.
+type topCounter struct {
+	capacity int
+	counts   map[string]int
+}
+
+func newTopCounter(capacity int) *topCounter {
+	return &topCounter{
+		capacity: capacity,
+		counts:   make(map[string]int, capacity),
+	}
+}
+
+func (t *topCounter) add(key string) {
+	if _, ok := t.counts[key]; ok || len(t.counts) < t.capacity {
+		t.counts[key]++
+		return
+	}
+
+	minKey, minCount := "", 0
+	for k, count := range t.counts {
+		if minKey == "" || count < minCount || (count == minCount && k < minKey) {
+			minKey, minCount = k, count
+		}
+	}
+	delete(t.counts, minKey)
+	t.counts[key] = minCount + 1
+}
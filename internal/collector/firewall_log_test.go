@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestFirewallLogAggregate(t *testing.T) {
+	c := &firewallLogCollector{
+		seen:   make(map[string]struct{}),
+		counts: make(map[firewallLogKey]float64),
+	}
+	if err := c.Configure(Settings{TopN: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blocked := func(digest, source string) opnsense.FirewallLogEntry {
+		return opnsense.FirewallLogEntry{Digest: digest, Interface: "igb1", Action: "block", Direction: "in", Protocol: "tcp", Label: "rule", Source: source, DestPort: "22"}
+	}
+
+	// the history of the log is not counted
+	c.aggregate([]opnsense.FirewallLogEntry{blocked("a", "198.51.100.1")})
+	if len(c.counts) != 0 {
+		t.Fatalf("expected the first entries to be skipped, got %v", c.counts)
+	}
+
+	// the entries that were already seen are not counted again
+	c.aggregate([]opnsense.FirewallLogEntry{blocked("c", "198.51.100.2"), blocked("b", "198.51.100.2"), blocked("a", "198.51.100.1")})
+	c.aggregate([]opnsense.FirewallLogEntry{blocked("c", "198.51.100.2")})
+
+	key := firewallLogKey{iface: "igb1", action: "block", direction: "in", protocol: "tcp", label: "rule"}
+	if got := c.counts[key]; got != 2 {
+		t.Errorf("expected 2 events, got %v", got)
+	}
+	if c.digest != "c" {
+		t.Errorf("expected the digest of the newest entry, got %q", c.digest)
+	}
+	if got := c.sources.counts["198.51.100.2"]; got != 2 {
+		t.Errorf("expected 2 events of the top source, got %d", got)
+	}
+	if got := c.ports.counts["tcp/22"]; got != 2 {
+		t.Errorf("expected 2 events of the top port, got %d", got)
+	}
+}
+
+func TestTopCounter(t *testing.T) {
+	counter := newTopCounter(2)
+	for _, key := range []string{"a", "a", "a", "b", "c", "c"} {
+		counter.add(key)
+	}
+
+	if len(counter.counts) != 2 {
+		t.Fatalf("expected at most 2 keys, got %v", counter.counts)
+	}
+	// c replaced b and took over its count
+	if counter.counts["a"] != 3 || counter.counts["c"] != 3 {
+		t.Errorf("unexpected counts %v", counter.counts)
+	}
+}
+
+func TestFirewallLogUpdate(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	coll := &firewallLogCollector{
+		subsystem: FirewallLogSubsystem,
+		seen:      make(map[string]struct{}),
+		counts:    make(map[firewallLogKey]float64),
+	}
+	if err := coll.Configure(Settings{TopN: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := newUpdateCollector(coll, server.Client(t))
+
+	// the first update only marks the entries of the fixture as seen
+	if count := testutil.CollectAndCount(c, "opnsense_firewall_log_events_total"); count != 0 {
+		t.Errorf("expected the history of the log not to be counted, got %d series", count)
+	}
+	query := server.Query("firewallLog")
+	if query.Get("limit") != "1000" || query.Has("digest") {
+		t.Errorf("expected the first update to request the latest entries without digest, got %v", query)
+	}
+
+	server.SetFault("firewallLog", opnsensetest.Fault{Body: []byte(`[
+		{"__digest__":"new2","interface":"igb1","action":"block","dir":"in","protoname":"tcp","label":"rule","src":"198.51.100.7","dstport":"443"},
+		{"__digest__":"new1","interface":"igb1","action":"block","dir":"in","protoname":"tcp","label":"rule","src":"198.51.100.7","dstport":"22"},
+		{"__digest__":"8c1f3e2d9b7a6f5e4d3c2b1a0f9e8d7c","interface":"igb1","action":"block","dir":"in","protoname":"tcp","label":"rule","src":"198.51.100.23","dstport":"22"}
+	]`)})
+
+	expected := `
+# HELP opnsense_firewall_log_events_total Total number of firewall log entries since the exporter started
+# TYPE opnsense_firewall_log_events_total counter
+opnsense_firewall_log_events_total{action="block",direction="in",interface="igb1",label="rule",opnsense_instance="test",protocol="tcp"} 2
+# HELP opnsense_firewall_log_top_blocked_source_events Approximate number of blocked firewall log entries of the most blocked source addresses since the exporter started
+# TYPE opnsense_firewall_log_top_blocked_source_events gauge
+opnsense_firewall_log_top_blocked_source_events{opnsense_instance="test",source="198.51.100.7"} 2
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"opnsense_firewall_log_events_total", "opnsense_firewall_log_top_blocked_source_events"); err != nil {
+		t.Error(err)
+	}
+	if digest := server.Query("firewallLog").Get("digest"); digest != "8c1f3e2d9b7a6f5e4d3c2b1a0f9e8d7c" {
+		t.Errorf("expected the update to continue from the newest entry of the previous update, got digest %q", digest)
+	}
+	if c.err != nil {
+		t.Errorf("expected no error, got %v", c.err)
+	}
+}
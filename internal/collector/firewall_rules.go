@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type firewallRulesCollector struct {
+	log *slog.Logger
+
+	evaluations    *prometheus.Desc
+	packets        *prometheus.Desc
+	bytes          *prometheus.Desc
+	states         *prometheus.Desc
+	stateCreations *prometheus.Desc
+
+	filter objectFilter
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &firewallRulesCollector{
+			subsystem: FirewallRulesSubsystem,
+		}
+	})
+}
+
+func (c *firewallRulesCollector) Name() string {
+	return c.subsystem
+}
+
+// Configure applies the include and exclude expressions,
+// which are matched against the description and the UUID of the rules.
+func (c *firewallRulesCollector) Configure(settings Settings) error {
+	filter, err := newObjectFilter(settings)
+	if err != nil {
+		return err
+	}
+	c.filter = filter
+	return nil
+}
+
+func (c *firewallRulesCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	labels := []string{"uuid", "description", "interface", "action"}
+
+	c.evaluations = buildPrometheusDesc("firewall_rule", "evaluations_total",
+		"Total number of evaluations of the firewall rule", labels)
+	c.packets = buildPrometheusDesc("firewall_rule", "packets_total",
+		"Total number of packets matched by the firewall rule", labels)
+	c.bytes = buildPrometheusDesc("firewall_rule", "bytes_total",
+		"Total number of bytes matched by the firewall rule", labels)
+	c.states = buildPrometheusDesc("firewall_rule", "states",
+		"Current number of states created by the firewall rule", labels)
+	c.stateCreations = buildPrometheusDesc("firewall_rule", "state_creations_total",
+		"Total number of states created by the firewall rule", labels)
+}
+
+func (c *firewallRulesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.evaluations
+	ch <- c.packets
+	ch <- c.bytes
+	ch <- c.states
+	ch <- c.stateCreations
+}
+
+func (c *firewallRulesCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"pfStatisticsRules", "firewallRules"}
+}
+
+func (c *firewallRulesCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchFirewallRuleStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range data {
+		if !c.filter.matches(rule.Description, rule.UUID) {
+			continue
+		}
+		labels := []string{rule.UUID, rule.Description, rule.Interface, rule.Action, c.instance}
+
+		ch <- prometheus.MustNewConstMetric(c.evaluations, prometheus.CounterValue, rule.Evaluations, labels...)
+		ch <- prometheus.MustNewConstMetric(c.packets, prometheus.CounterValue, rule.Packets, labels...)
+		ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.CounterValue, rule.Bytes, labels...)
+		ch <- prometheus.MustNewConstMetric(c.states, prometheus.GaugeValue, rule.States, labels...)
+		ch <- prometheus.MustNewConstMetric(c.stateCreations, prometheus.CounterValue, rule.StateCreations, labels...)
+	}
+
+	return nil
+}
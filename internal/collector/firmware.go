@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 	"strconv"
 
@@ -26,8 +27,10 @@ type firmwareCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &firmwareCollector{
-		subsystem: FirmwareSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &firmwareCollector{
+			subsystem: FirmwareSubsystem,
+		}
 	})
 }
 
@@ -87,8 +90,12 @@ func (c *firmwareCollector) update(ch chan<- prometheus.Metric, desc *prometheus
 	)
 }
 
-func (c *firmwareCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchFirmwareStatus()
+func (c *firmwareCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"firmware"}
+}
+
+func (c *firmwareCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchFirmwareStatus(ctx)
 	if err != nil {
 		return err
 	}
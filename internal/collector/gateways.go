@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 	"strconv"
 
@@ -28,11 +29,11 @@ type gatewaysCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances,
-		&gatewaysCollector{
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &gatewaysCollector{
 			subsystem: GatewaysSubsystem,
-		},
-	)
+		}
+	})
 }
 
 func (c *gatewaysCollector) Name() string {
@@ -110,13 +111,27 @@ func (c *gatewaysCollector) Register(namespace, instanceLabel string, log *slog.
 }
 
 func (c *gatewaysCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.monitor
 	ch <- c.status
 	ch <- c.lossPercentage
+	ch <- c.lossLow
+	ch <- c.lossHigh
 	ch <- c.rtt
+	ch <- c.rttd
+	ch <- c.rttLow
+	ch <- c.rttHigh
+	ch <- c.interval
+	ch <- c.period
+	ch <- c.timeout
+}
+
+func (c *gatewaysCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"gatewaysStatus"}
 }
 
-func (c *gatewaysCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchGateways()
+func (c *gatewaysCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchGateways(ctx)
 	if err != nil {
 		return err
 	}
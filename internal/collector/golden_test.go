@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/promslog"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// nondeterministicMetrics hold the metrics that depend on the time of the scrape
+// and are left out of the golden files.
+var nondeterministicMetrics = map[string]bool{
+	"opnsense_exporter_api_request_duration_seconds":             true,
+	"opnsense_exporter_collector_duration_seconds":               true,
+	"opnsense_exporter_collector_last_success_timestamp_seconds": true,
+}
+
+func TestGoldenMetrics(t *testing.T) {
+	for _, release := range opnsensetest.Releases {
+		t.Run(release, func(t *testing.T) {
+			server := opnsensetest.NewServer(t, release)
+
+			// the optional metrics are enabled to cover them by the golden files
+			collector, err := New(server.Client(t), promslog.NewNopLogger(), "test",
+				WithCollectorSettings(DHCPv4Subsystem, Settings{
+					Details: true,
+					Pools:   map[string]string{"LAN": "192.168.1.100-192.168.1.199"},
+				}),
+				WithCollectorSettings(KeaSubsystem, Settings{Details: true}),
+				WithCollectorSettings(DnsmasqSubsystem, Settings{Details: true}),
+				WithCollectorSettings(UnboundDNSBLSubsystem, Settings{TopN: 2}),
+				WithCollectorSettings(FirewallRulesSubsystem, Settings{Exclude: "Block bogons"}),
+				WithExplicitCollector(FirewallLogSubsystem),
+				WithCollectorSettings(FirewallLogSubsystem, Settings{TopN: 2}),
+				WithCollectorSettings(RoutesSubsystem, Settings{Details: true}),
+				WithCollectorSettings(NdpSubsystem, Settings{Details: true}),
+			)
+			if err != nil {
+				t.Fatalf("expected no error when creating collector, got %v", err)
+			}
+
+			// the plugins are detected like on the start of the exporter,
+			// so the collectors of the APIs that are missing on the release are disabled
+			collector.detectPlugins(context.Background())
+
+			registry := prometheus.NewPedanticRegistry()
+			registry.MustRegister(collector)
+
+			families, err := registry.Gather()
+			if err != nil {
+				t.Fatalf("expected no error when gathering, got %v", err)
+			}
+
+			var got bytes.Buffer
+			for _, family := range families {
+				if nondeterministicMetrics[family.GetName()] {
+					continue
+				}
+				if _, err := expfmt.MetricFamilyToText(&got, family); err != nil {
+					t.Fatalf("failed to encode %s: %v", family.GetName(), err)
+				}
+			}
+
+			golden := filepath.Join("testdata", "golden", release+".prom")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file, run with -update to create it: %v", err)
+			}
+			if !bytes.Equal(got.Bytes(), expected) {
+				t.Errorf("metrics differ from %s, run with -update to accept the changes:\n%s", golden, got.String())
+			}
+		})
+	}
+}
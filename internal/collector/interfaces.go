@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -18,14 +19,26 @@ type interfacesCollector struct {
 	inputErrors           *prometheus.Desc
 	outputErrors          *prometheus.Desc
 	collisions            *prometheus.Desc
+	packetsReceived       *prometheus.Desc
+	packetsTransmitted    *prometheus.Desc
+	linkUp                *prometheus.Desc
+	lineRate              *prometheus.Desc
+	sendQueueLength       *prometheus.Desc
+	sendQueueMaxLength    *prometheus.Desc
+	sendQueueDrops        *prometheus.Desc
+	inputQueueDrops       *prometheus.Desc
+	unknownProtocol       *prometheus.Desc
+	statsReset            *prometheus.Desc
 
 	subsystem string
 	instance  string
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &interfacesCollector{
-		subsystem: InterfacesSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &interfacesCollector{
+			subsystem: InterfacesSubsystem,
+		}
 	})
 }
 
@@ -71,6 +84,46 @@ func (c *interfacesCollector) Register(namespace, instanceLabel string, log *slo
 		"Collisions on this interface by interface name and device",
 		[]string{"interface", "device", "type"},
 	)
+	c.packetsReceived = buildPrometheusDesc(c.subsystem, "received_packets_total",
+		"Packets received on this interface by interface name and device",
+		[]string{"interface", "device", "type"},
+	)
+	c.packetsTransmitted = buildPrometheusDesc(c.subsystem, "transmitted_packets_total",
+		"Packets transmitted on this interface by interface name and device",
+		[]string{"interface", "device", "type"},
+	)
+	c.linkUp = buildPrometheusDesc(c.subsystem, "link_up",
+		"Whether the link of this interface is up (1 = up, 0 = down)",
+		[]string{"interface", "device", "type"},
+	)
+	c.lineRate = buildPrometheusDesc(c.subsystem, "line_rate_bits_per_second",
+		"Line rate of this interface in bits per second",
+		[]string{"interface", "device", "type"},
+	)
+	c.sendQueueLength = buildPrometheusDesc(c.subsystem, "send_queue_length",
+		"Current length of the send queue of this interface",
+		[]string{"interface", "device", "type"},
+	)
+	c.sendQueueMaxLength = buildPrometheusDesc(c.subsystem, "send_queue_max_length",
+		"Maximum length of the send queue of this interface",
+		[]string{"interface", "device", "type"},
+	)
+	c.sendQueueDrops = buildPrometheusDesc(c.subsystem, "send_queue_drops_total",
+		"Packets dropped because the send queue of this interface was full",
+		[]string{"interface", "device", "type"},
+	)
+	c.inputQueueDrops = buildPrometheusDesc(c.subsystem, "input_queue_drops_total",
+		"Packets dropped because the input queue of this interface was full",
+		[]string{"interface", "device", "type"},
+	)
+	c.unknownProtocol = buildPrometheusDesc(c.subsystem, "unknown_protocol_packets_total",
+		"Packets received on this interface for an unknown protocol",
+		[]string{"interface", "device", "type"},
+	)
+	c.statsReset = buildPrometheusDesc(c.subsystem, "stats_reset_timestamp_seconds",
+		"Unix timestamp of the time this interface was attached or its counters were reset",
+		[]string{"interface", "device", "type"},
+	)
 }
 
 func (c *interfacesCollector) Describe(ch chan<- *prometheus.Desc) {
@@ -82,6 +135,16 @@ func (c *interfacesCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.inputErrors
 	ch <- c.outputErrors
 	ch <- c.collisions
+	ch <- c.packetsReceived
+	ch <- c.packetsTransmitted
+	ch <- c.linkUp
+	ch <- c.lineRate
+	ch <- c.sendQueueLength
+	ch <- c.sendQueueMaxLength
+	ch <- c.sendQueueDrops
+	ch <- c.inputQueueDrops
+	ch <- c.unknownProtocol
+	ch <- c.statsReset
 }
 
 func (c *interfacesCollector) update(ch chan<- prometheus.Metric, desc *prometheus.Desc, valueType prometheus.ValueType, value float64, labelValues ...string) {
@@ -90,12 +153,24 @@ func (c *interfacesCollector) update(ch chan<- prometheus.Metric, desc *promethe
 	)
 }
 
-func (c *interfacesCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchInterfaces()
+func (c *interfacesCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"interfaces", "systemTime"}
+}
+
+func (c *interfacesCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchInterfaces(ctx)
 	if err != nil {
 		return err
 	}
 
+	// OPNsense only returns the uptime of the system at the reset of the counters,
+	// the time of the reset is derived from the boot time of the system
+	systemTime, timeErr := client.FetchSystemTime(ctx)
+	if timeErr != nil {
+		c.log.Error("failed to fetch the boot time for the reset of the interface counters",
+			"collector", c.Name(), "err", timeErr.Error())
+	}
+
 	for _, iface := range data.Interfaces {
 		c.update(ch, c.mtu, prometheus.GaugeValue, float64(iface.MTU), iface.Name, iface.Device, iface.Type, c.instance)
 		c.update(ch, c.bytesReceived, prometheus.CounterValue, float64(iface.BytesReceived), iface.Name, iface.Device, iface.Type, c.instance)
@@ -105,7 +180,41 @@ func (c *interfacesCollector) Update(client *opnsense.Client, ch chan<- promethe
 		c.update(ch, c.inputErrors, prometheus.CounterValue, float64(iface.InputErrors), iface.Name, iface.Device, iface.Type, c.instance)
 		c.update(ch, c.outputErrors, prometheus.CounterValue, float64(iface.OutputErrors), iface.Name, iface.Device, iface.Type, c.instance)
 		c.update(ch, c.collisions, prometheus.CounterValue, float64(iface.Collisions), iface.Name, iface.Device, iface.Type, c.instance)
+		c.update(ch, c.packetsReceived, prometheus.CounterValue, float64(iface.PacketsReceived), iface.Name, iface.Device, iface.Type, c.instance)
+		c.update(ch, c.packetsTransmitted, prometheus.CounterValue, float64(iface.PacketsTransmitted), iface.Name, iface.Device, iface.Type, c.instance)
+
+		// malformed values of the interface are -1 and left out
+		optional := []struct {
+			desc      *prometheus.Desc
+			valueType prometheus.ValueType
+			value     int
+		}{
+			{c.lineRate, prometheus.GaugeValue, iface.LineRate},
+			{c.sendQueueLength, prometheus.GaugeValue, iface.SendQueueLength},
+			{c.sendQueueMaxLength, prometheus.GaugeValue, iface.SendQueueMaxLength},
+			{c.sendQueueDrops, prometheus.CounterValue, iface.SendQueueDrops},
+			{c.inputQueueDrops, prometheus.CounterValue, iface.InputQueueDrops},
+			{c.unknownProtocol, prometheus.CounterValue, iface.PacketsForUnknownProtocol},
+		}
+		for _, o := range optional {
+			if o.value >= 0 {
+				c.update(ch, o.desc, o.valueType, float64(o.value), iface.Name, iface.Device, iface.Type, c.instance)
+			}
+		}
+
+		if timeErr == nil && iface.StatsResetSystemUptime >= 0 {
+			reset := systemTime.BootTime.Unix() + int64(iface.StatsResetSystemUptime)
+			c.update(ch, c.statsReset, prometheus.GaugeValue, float64(reset), iface.Name, iface.Device, iface.Type, c.instance)
+		}
+
+		// interfaces without a link state, e.g. the loopback, have no link to report
+		switch iface.LinkState {
+		case 1:
+			c.update(ch, c.linkUp, prometheus.GaugeValue, 0, iface.Name, iface.Device, iface.Type, c.instance)
+		case 2:
+			c.update(ch, c.linkUp, prometheus.GaugeValue, 1, iface.Name, iface.Device, iface.Type, c.instance)
+		}
 	}
 
-	return nil
+	return timeErr
 }
@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type interfacesOverviewCollector struct {
+	log *slog.Logger
+
+	info        *prometheus.Desc
+	addressInfo *prometheus.Desc
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &interfacesOverviewCollector{
+			subsystem: InterfacesOverviewSubsystem,
+		}
+	})
+}
+
+func (c *interfacesOverviewCollector) Name() string {
+	return c.subsystem
+}
+
+func (c *interfacesOverviewCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.info = buildPrometheusDesc(c.subsystem, "info",
+		"Configuration of the logical interface, always 1",
+		[]string{"identifier", "description", "device", "vlan_tag", "vlan_parent", "enabled"})
+	c.addressInfo = buildPrometheusDesc(c.subsystem, "address_info",
+		"Address of the logical interface with its prefix length, always 1",
+		[]string{"identifier", "device", "family", "address", "prefix_length"})
+}
+
+func (c *interfacesOverviewCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.info
+	ch <- c.addressInfo
+}
+
+func (c *interfacesOverviewCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"interfacesOverview"}
+}
+
+func (c *interfacesOverviewCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchInterfacesOverview(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, iface := range data {
+		ch <- prometheus.MustNewConstMetric(
+			c.info,
+			prometheus.GaugeValue,
+			1,
+			iface.Identifier,
+			iface.Description,
+			iface.Device,
+			iface.VlanTag,
+			iface.VlanParent,
+			strconv.FormatBool(iface.Enabled),
+			c.instance,
+		)
+
+		for family, addresses := range map[string][]opnsense.InterfaceAddress{"ipv4": iface.IPv4, "ipv6": iface.IPv6} {
+			for _, address := range addresses {
+				ch <- prometheus.MustNewConstMetric(
+					c.addressInfo,
+					prometheus.GaugeValue,
+					1,
+					iface.Identifier,
+					iface.Device,
+					family,
+					address.Address,
+					address.PrefixLength,
+					c.instance,
+				)
+			}
+		}
+	}
+
+	return nil
+}
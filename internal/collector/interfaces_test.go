@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInterfacesMalformedValues(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	server.SetFault("interfaces", opnsensetest.Fault{Body: []byte(`{"interfaces":{
+		"lan":{"name":"LAN","device":"igb1","type":"Ethernet","mtu":"1500",
+			"packets received":"10","packets transmitted":"20","bytes received":"100","bytes transmitted":"200",
+			"multicasts received":"0","multicasts transmitted":"0","input errors":"0","output errors":"0","collisions":"0",
+			"link state":"2","line rate":"1000000000 bit/s","send queue length":"0","send queue max length":"1024",
+			"send queue drops":"0","input queue drops":"0","packets for unknown protocol":"0","uptime at attach or stat reset":"1"},
+		"wan":{"name":"WAN","device":"igb0","type":"Ethernet","mtu":"1500",
+			"packets received":"10","packets transmitted":"20","bytes received":"100","bytes transmitted":"200",
+			"multicasts received":"0","multicasts transmitted":"0","input errors":"0","output errors":"0","collisions":"0",
+			"link state":"unknown","line rate":"n/a","send queue length":"0","send queue max length":"1024",
+			"send queue drops":"0","input queue drops":"0","packets for unknown protocol":"0","uptime at attach or stat reset":""}
+	}}`)})
+	c := newUpdateCollector(&interfacesCollector{subsystem: InterfacesSubsystem}, server.Client(t))
+
+	if count := testutil.CollectAndCount(c, "opnsense_interfaces_received_bytes_total"); count != 2 {
+		t.Errorf("expected both interfaces to be collected, got %d series", count)
+	}
+	if count := testutil.CollectAndCount(c, "opnsense_interfaces_send_queue_max_length"); count != 2 {
+		t.Errorf("expected the valid values of both interfaces, got %d series", count)
+	}
+	for _, metric := range []string{
+		"opnsense_interfaces_link_up",
+		"opnsense_interfaces_line_rate_bits_per_second",
+		"opnsense_interfaces_stats_reset_timestamp_seconds",
+	} {
+		if count := testutil.CollectAndCount(c, metric); count != 1 {
+			t.Errorf("expected %s of the interface with a valid value only, got %d series", metric, count)
+		}
+	}
+	if c.err != nil {
+		t.Errorf("expected no error, got %v", c.err)
+	}
+}
+
+func TestInterfacesStatsReset(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	c := newUpdateCollector(&interfacesCollector{subsystem: InterfacesSubsystem}, server.Client(t))
+
+	// the fixture system booted at 2024-07-30 06:07:58 UTC and the counters were reset 1 second later
+	expected := `
+# HELP opnsense_interfaces_stats_reset_timestamp_seconds Unix timestamp of the time this interface was attached or its counters were reset
+# TYPE opnsense_interfaces_stats_reset_timestamp_seconds gauge
+opnsense_interfaces_stats_reset_timestamp_seconds{device="igb0",interface="WAN",opnsense_instance="test",type="Ethernet"} 1.722319679e+09
+opnsense_interfaces_stats_reset_timestamp_seconds{device="igb1",interface="LAN",opnsense_instance="test",type="Ethernet"} 1.722319679e+09
+opnsense_interfaces_stats_reset_timestamp_seconds{device="lo0",interface="Loopback",opnsense_instance="test",type="Loopback"} 1.722319679e+09
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "opnsense_interfaces_stats_reset_timestamp_seconds"); err != nil {
+		t.Error(err)
+	}
+
+	// without the boot time only the reset timestamp is left out
+	server.SetFault("systemTime", opnsensetest.Fault{StatusCode: http.StatusForbidden})
+	if count := testutil.CollectAndCount(c, "opnsense_interfaces_stats_reset_timestamp_seconds"); count != 0 {
+		t.Errorf("expected no reset timestamps without the boot time, got %d series", count)
+	}
+	if count := testutil.CollectAndCount(c, "opnsense_interfaces_received_bytes_total"); count != 3 {
+		t.Errorf("expected the interfaces to be collected, got %d series", count)
+	}
+	if c.err == nil || c.err.StatusCode != http.StatusForbidden {
+		t.Errorf("expected the error of the system time endpoint, got %v", c.err)
+	}
+}
@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -29,8 +30,10 @@ type ipsecCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &ipsecCollector{
-		subsystem: IPsecSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &ipsecCollector{
+			subsystem: IPsecSubsystem,
+		}
 	})
 }
 
@@ -116,8 +119,12 @@ func (c *ipsecCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.phase2_life_time
 }
 
-func (c *ipsecCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	phase1s, err := client.FetchIPsecPhase1()
+func (c *ipsecCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"ipsecPhase1", "ipsecPhase2"}
+}
+
+func (c *ipsecCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	phase1s, err := client.FetchIPsecPhase1(ctx)
 	if err != nil {
 		return err
 	}
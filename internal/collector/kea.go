@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// keaCollector exposes the leases and pools of the Kea DHCPv4 and DHCPv6 servers
+// with the same metrics as the dhcpv4 collector and backend="kea".
+type keaCollector struct {
+	v4 leaseMetrics
+	v6 leaseMetrics
+
+	log       *slog.Logger
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &keaCollector{
+			subsystem: KeaSubsystem,
+		}
+	})
+}
+
+func (c *keaCollector) Name() string {
+	return c.subsystem
+}
+
+// Configure applies the lease details switch.
+// The pools of Kea are fetched from its API, so configured pools are ignored.
+func (c *keaCollector) Configure(settings Settings) error {
+	c.v4.details = settings.Details
+	c.v6.details = settings.Details
+	return nil
+}
+
+func (c *keaCollector) Register(namespace, instance string, log *slog.Logger) {
+	c.log = log
+	c.instance = instance
+
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.v4.register(DHCPv4Subsystem)
+	c.v6.register(DHCPv6Subsystem)
+}
+
+func (c *keaCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.v4.describe(ch)
+	c.v6.describe(ch)
+}
+
+func (c *keaCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"keaLeases4", "keaSubnets4", "keaLeases6", "keaSubnets6"}
+}
+
+// Update fetches the DHCPv4 and DHCPv6 leases independently, so the metrics of one
+// protocol are exposed when the other fails, e.g. when only one of the Kea servers is set up.
+func (c *keaCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	leases4, err4 := client.FetchKeaLeases4(ctx)
+	if err4 == nil {
+		c.v4.collect(ch, c.instance, "kea", leases4)
+	}
+
+	leases6, err6 := client.FetchKeaLeases6(ctx)
+	if err6 == nil {
+		c.v6.collect(ch, c.instance, "kea", leases6)
+	}
+
+	if err4 != nil {
+		if err6 != nil {
+			c.log.Error("failed to fetch the DHCPv6 leases", "collector", c.Name(), "err", err6)
+		}
+		return err4
+	}
+	return err6
+}
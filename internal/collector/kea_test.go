@@ -0,0 +1,40 @@
+package collector
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestKeaPartialUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		faulty   opnsense.EndpointName
+		expected string
+		missing  string
+	}{
+		{name: "DHCPv6 fails", faulty: "keaLeases6", expected: "opnsense_dhcpv4_leases", missing: "opnsense_dhcpv6_leases"},
+		{name: "DHCPv4 fails", faulty: "keaSubnets4", expected: "opnsense_dhcpv6_leases", missing: "opnsense_dhcpv4_leases"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := opnsensetest.NewServer(t, "25.7")
+			server.SetFault(tc.faulty, opnsensetest.Fault{StatusCode: http.StatusInternalServerError})
+			c := newUpdateCollector(&keaCollector{subsystem: KeaSubsystem}, server.Client(t))
+
+			if count := testutil.CollectAndCount(c, tc.expected); count == 0 {
+				t.Errorf("expected %s to be collected", tc.expected)
+			}
+			if count := testutil.CollectAndCount(c, tc.missing); count != 0 {
+				t.Errorf("expected %s not to be collected, got %d series", tc.missing, count)
+			}
+			if c.err == nil || c.err.StatusCode != http.StatusInternalServerError {
+				t.Errorf("expected the error of %s, got %v", tc.faulty, c.err)
+			}
+		})
+	}
+}
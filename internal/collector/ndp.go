@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ndpInterface holds the labels the NDP entries are counted by.
+type ndpInterface struct {
+	iface       string
+	description string
+}
+
+type ndpCollector struct {
+	entries   *prometheus.Desc
+	neighbors *prometheus.Desc
+	details   bool
+	log       *slog.Logger
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &ndpCollector{
+			subsystem: NdpSubsystem,
+		}
+	})
+}
+
+func (c *ndpCollector) Name() string {
+	return c.subsystem
+}
+
+// Configure enables the metric per NDP entry.
+func (c *ndpCollector) Configure(settings Settings) error {
+	c.details = settings.Details
+	return nil
+}
+
+func (c *ndpCollector) Register(namespace, instance string, log *slog.Logger) {
+	c.log = log
+	c.instance = instance
+
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.entries = buildPrometheusDesc(c.subsystem, "entries",
+		"NDP entries by ip, mac, interface, interface description and manufacturer",
+		[]string{"ip", "mac", "interface", "interface_description", "manufacturer"},
+	)
+	c.neighbors = buildPrometheusDesc(c.subsystem, "neighbors",
+		"Number of NDP entries by interface and interface description",
+		[]string{"interface", "interface_description"},
+	)
+}
+
+func (c *ndpCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entries
+	ch <- c.neighbors
+}
+
+func (c *ndpCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"ndp"}
+}
+
+func (c *ndpCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchNdpTable(ctx)
+	if err != nil {
+		return err
+	}
+
+	neighbors := make(map[ndpInterface]int)
+	for _, ndp := range data.Ndp {
+		neighbors[ndpInterface{iface: ndp.Intf, description: ndp.IntfDescription}]++
+
+		if !c.details {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.entries,
+			prometheus.GaugeValue,
+			1,
+			ndp.IP,
+			ndp.Mac,
+			ndp.Intf,
+			ndp.IntfDescription,
+			ndp.Manufacturer,
+			c.instance,
+		)
+	}
+
+	for iface, count := range neighbors {
+		ch <- prometheus.MustNewConstMetric(
+			c.neighbors,
+			prometheus.GaugeValue,
+			float64(count),
+			iface.iface,
+			iface.description,
+			c.instance,
+		)
+	}
+
+	return nil
+}
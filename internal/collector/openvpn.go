@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -17,8 +18,10 @@ type openVPNCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &openVPNCollector{
-		subsystem: OpenVPNSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &openVPNCollector{
+			subsystem: OpenVPNSubsystem,
+		}
 	})
 }
 
@@ -47,8 +50,12 @@ func (c *openVPNCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.sessions
 }
 
-func (c *openVPNCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	instances, err := client.FetchOpenVPNInstances()
+func (c *openVPNCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"openVPNInstances", "openVPNSessions"}
+}
+
+func (c *openVPNCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	instances, err := client.FetchOpenVPNInstances(ctx)
 	if err != nil {
 		return err
 	}
@@ -65,7 +72,7 @@ func (c *openVPNCollector) Update(client *opnsense.Client, ch chan<- prometheus.
 		)
 	}
 
-	sessions, err := client.FetchOpenVPNSessions()
+	sessions, err := client.FetchOpenVPNSessions(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,153 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type pfCollector struct {
+	log *slog.Logger
+
+	stateEntries    *prometheus.Desc
+	stateLimit      *prometheus.Desc
+	stateSearches   *prometheus.Desc
+	stateInserts    *prometheus.Desc
+	stateRemovals   *prometheus.Desc
+	srcNodeEntries  *prometheus.Desc
+	srcNodeLimit    *prometheus.Desc
+	srcNodeSearches *prometheus.Desc
+	srcNodeInserts  *prometheus.Desc
+	srcNodeRemovals *prometheus.Desc
+	fragmentLimit   *prometheus.Desc
+	tableEntryLimit *prometheus.Desc
+	counters        *prometheus.Desc
+	limitCounters   *prometheus.Desc
+	timeouts        *prometheus.Desc
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &pfCollector{
+			subsystem: PFSubsystem,
+		}
+	})
+}
+
+func (c *pfCollector) Name() string {
+	return c.subsystem
+}
+
+func (c *pfCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.stateEntries = buildPrometheusDesc(c.subsystem, "state_entries",
+		"Current number of entries in the state table", nil)
+	c.stateLimit = buildPrometheusDesc(c.subsystem, "state_limit",
+		"Hard limit of entries in the state table", nil)
+	c.stateSearches = buildPrometheusDesc(c.subsystem, "state_searches_total",
+		"Total number of searches in the state table", nil)
+	c.stateInserts = buildPrometheusDesc(c.subsystem, "state_inserts_total",
+		"Total number of inserts into the state table", nil)
+	c.stateRemovals = buildPrometheusDesc(c.subsystem, "state_removals_total",
+		"Total number of removals from the state table", nil)
+	c.srcNodeEntries = buildPrometheusDesc(c.subsystem, "src_node_entries",
+		"Current number of entries in the source tracking table", nil)
+	c.srcNodeLimit = buildPrometheusDesc(c.subsystem, "src_node_limit",
+		"Hard limit of entries in the source tracking table", nil)
+	c.srcNodeSearches = buildPrometheusDesc(c.subsystem, "src_node_searches_total",
+		"Total number of searches in the source tracking table", nil)
+	c.srcNodeInserts = buildPrometheusDesc(c.subsystem, "src_node_inserts_total",
+		"Total number of inserts into the source tracking table", nil)
+	c.srcNodeRemovals = buildPrometheusDesc(c.subsystem, "src_node_removals_total",
+		"Total number of removals from the source tracking table", nil)
+	c.fragmentLimit = buildPrometheusDesc(c.subsystem, "fragment_limit",
+		"Hard limit of packet fragments held for reassembly", nil)
+	c.tableEntryLimit = buildPrometheusDesc(c.subsystem, "table_entry_limit",
+		"Hard limit of addresses in all tables", nil)
+	c.counters = buildPrometheusDesc(c.subsystem, "counters_total",
+		"Total number of packets by pf counter (match, fragment, memory, congestion, state-mismatch, ...)",
+		[]string{"reason"})
+	c.limitCounters = buildPrometheusDesc(c.subsystem, "limit_counters_total",
+		"Total number of times a pf limit was hit by limit (max-states-per-rule, max-src-conn, ...)",
+		[]string{"limit"})
+	c.timeouts = buildPrometheusDesc(c.subsystem, "timeout_seconds",
+		"Timeout of pf by name (tcp.established, udp.first, ...) in seconds",
+		[]string{"name"})
+}
+
+func (c *pfCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.stateEntries
+	ch <- c.stateLimit
+	ch <- c.stateSearches
+	ch <- c.stateInserts
+	ch <- c.stateRemovals
+	ch <- c.srcNodeEntries
+	ch <- c.srcNodeLimit
+	ch <- c.srcNodeSearches
+	ch <- c.srcNodeInserts
+	ch <- c.srcNodeRemovals
+	ch <- c.fragmentLimit
+	ch <- c.tableEntryLimit
+	ch <- c.counters
+	ch <- c.limitCounters
+	ch <- c.timeouts
+}
+
+func (c *pfCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"pfStatisticsInfo", "pfStatisticsMemory", "pfStatisticsTimeouts"}
+}
+
+func (c *pfCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchPFInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, metric := range []struct {
+		desc      *prometheus.Desc
+		valueType prometheus.ValueType
+		value     float64
+	}{
+		{c.stateEntries, prometheus.GaugeValue, data.States.Entries},
+		{c.stateSearches, prometheus.CounterValue, data.States.Searches},
+		{c.stateInserts, prometheus.CounterValue, data.States.Inserts},
+		{c.stateRemovals, prometheus.CounterValue, data.States.Removals},
+		{c.srcNodeEntries, prometheus.GaugeValue, data.SrcNodes.Entries},
+		{c.srcNodeSearches, prometheus.CounterValue, data.SrcNodes.Searches},
+		{c.srcNodeInserts, prometheus.CounterValue, data.SrcNodes.Inserts},
+		{c.srcNodeRemovals, prometheus.CounterValue, data.SrcNodes.Removals},
+	} {
+		ch <- prometheus.MustNewConstMetric(metric.desc, metric.valueType, metric.value, c.instance)
+	}
+
+	for pool, desc := range map[string]*prometheus.Desc{
+		"states":        c.stateLimit,
+		"src-nodes":     c.srcNodeLimit,
+		"frags":         c.fragmentLimit,
+		"table-entries": c.tableEntryLimit,
+	} {
+		if limit, ok := data.Limits[pool]; ok {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, limit, c.instance)
+		}
+	}
+
+	for reason, value := range data.Counters {
+		ch <- prometheus.MustNewConstMetric(c.counters, prometheus.CounterValue, value, reason, c.instance)
+	}
+	for limit, value := range data.LimitCounters {
+		ch <- prometheus.MustNewConstMetric(c.limitCounters, prometheus.CounterValue, value, limit, c.instance)
+	}
+	for name, value := range data.Timeouts {
+		ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.GaugeValue, value, name, c.instance)
+	}
+
+	return nil
+}
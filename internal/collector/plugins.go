@@ -0,0 +1,190 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pluginRequirement describes the OPNsense release and plugin that provide the API of a collector.
+// The API is available since the release in since. Features that moved into
+// the core are available without the plugin since the release in coreSince,
+// features that moved out of the core need the plugin since the release in coreUntil.
+type pluginRequirement struct {
+	plugin    string
+	since     string
+	coreSince string
+	coreUntil string
+}
+
+// collectorPlugins holds the plugin requirements of the collectors by collector name.
+// Collectors without an entry are backed by the OPNsense core and always enabled.
+var collectorPlugins = map[string]pluginRequirement{
+	WireguardSubsystem:          {plugin: "os-wireguard", coreSince: "24.1"},
+	DHCPv4Subsystem:             {plugin: "os-isc-dhcp", coreUntil: "26.1"},
+	KeaSubsystem:                {since: "24.1"},
+	DnsmasqSubsystem:            {since: "25.1"},
+	UnboundDNSBLSubsystem:       {since: "23.7"},
+	FirewallRulesSubsystem:      {plugin: "os-firewall", coreSince: "24.1"},
+	InterfacesOverviewSubsystem: {since: "23.7"},
+	SystemSubsystem:             {since: "23.7"},
+	TemperatureSubsystem:        {since: "23.7"},
+}
+
+// available reports whether the API of the requirement is available
+// on the OPNsense version with the installed plugins.
+// The API is assumed to be available on unparsable versions, e.g. of development builds.
+func (r pluginRequirement) available(version string, plugins map[string]string) bool {
+	if parseVersion(version) == nil {
+		return true
+	}
+	if r.since != "" && !versionAtLeast(version, r.since) {
+		return false
+	}
+	if r.plugin == "" {
+		return true
+	}
+	if _, installed := plugins[r.plugin]; installed {
+		return true
+	}
+	if r.coreSince != "" && versionAtLeast(version, r.coreSince) {
+		return true
+	}
+	return r.coreUntil != "" && !versionAtLeast(version, r.coreUntil)
+}
+
+// WithPluginDetection Option
+// queries the installed OPNsense plugins at the interval and skips
+// the collectors whose backing plugin is missing. 0 disables the detection.
+func WithPluginDetection(interval time.Duration) Option {
+	return func(o *Collector) error {
+		o.pluginDetectionInterval = interval
+		return nil
+	}
+}
+
+// WithExplicitCollector Option
+// marks the collector with the given name as explicitly enabled,
+// so it is never disabled by the plugin detection.
+func WithExplicitCollector(name string) Option {
+	return func(o *Collector) error {
+		for _, collector := range o.collectors {
+			if collector.Name() == name {
+				o.explicitCollectors[name] = true
+				return nil
+			}
+		}
+		return fmt.Errorf("collector %s not found", name)
+	}
+}
+
+// autoDisabled reports whether the collector is disabled by the plugin detection.
+func (c *Collector) autoDisabled(name string) bool {
+	c.pluginsMutex.RLock()
+	defer c.pluginsMutex.RUnlock()
+
+	_, ok := c.autoDisabledCollectors[name]
+	return ok
+}
+
+// detectPlugins fetches the OPNsense version and the installed plugins and updates
+// the collectors that are disabled because their release or plugin is missing.
+// On failure the previous detection result is kept.
+func (c *Collector) detectPlugins(ctx context.Context) {
+	info, err := c.Client.FetchFirmwareInfo(ctx)
+	if err != nil {
+		c.log.Warn("failed to detect the installed plugins", "err", err)
+		c.endpointErrors.WithLabelValues(err.Endpoint, c.instanceLabel).Inc()
+		return
+	}
+
+	if parseVersion(info.ProductVersion) == nil {
+		c.log.Warn("failed to parse the OPNsense version; collectors are not disabled by release",
+			"version", info.ProductVersion)
+	}
+
+	disabled := make(map[string]pluginRequirement)
+	for _, collector := range c.collectors {
+		requirement, ok := collectorPlugins[collector.Name()]
+		if !ok || c.explicitCollectors[collector.Name()] {
+			continue
+		}
+		if requirement.available(info.ProductVersion, info.Plugins) {
+			continue
+		}
+		disabled[collector.Name()] = requirement
+	}
+
+	c.pluginsMutex.Lock()
+	defer c.pluginsMutex.Unlock()
+
+	for name, requirement := range disabled {
+		if _, ok := c.autoDisabledCollectors[name]; !ok {
+			c.log.Info("collector disabled; its API is not available",
+				"collector", name, "plugin", requirement.plugin, "min_version", requirement.since, "version", info.ProductVersion)
+		}
+	}
+	for name := range c.autoDisabledCollectors {
+		if _, ok := disabled[name]; !ok {
+			c.log.Info("collector enabled; its API is available", "collector", name)
+		}
+	}
+	c.autoDisabledCollectors = disabled
+}
+
+// detectPluginsPeriodically runs the plugin detection at the interval until the context is done.
+func (c *Collector) detectPluginsPeriodically(ctx context.Context, interval time.Duration) {
+	defer c.polling.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		detectCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		c.detectPlugins(detectCtx)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseVersion parses the numeric parts of the OPNsense version (e.g. "24.7.3", "25.1_2" or "25.7.r1").
+// The parsing stops at the first part that is not a number; nil means that the version is unparsable.
+func parseVersion(version string) []int {
+	version, _, _ = strings.Cut(version, "_")
+	version, _, _ = strings.Cut(version, "-")
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// versionAtLeast reports whether the OPNsense version (e.g. "24.7.3" or "25.1_2")
+// is at least the minimum release (e.g. "24.1"). Unparsable versions are never at least the minimum.
+func versionAtLeast(version, minimum string) bool {
+	current, required := parseVersion(version), parseVersion(minimum)
+	if current == nil || required == nil {
+		return false
+	}
+
+	for i, req := range required {
+		if i >= len(current) {
+			return false
+		}
+		if current[i] != req {
+			return current[i] > req
+		}
+	}
+	return true
+}
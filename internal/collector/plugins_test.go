@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		minimum  string
+		expected bool
+	}{
+		{name: "Equal", version: "24.1", minimum: "24.1", expected: true},
+		{name: "Newer minor", version: "24.7.3", minimum: "24.1", expected: true},
+		{name: "Newer major", version: "25.1_2", minimum: "24.7", expected: true},
+		{name: "Older", version: "23.7.12", minimum: "24.1", expected: false},
+		{name: "Shorter version", version: "24.7", minimum: "24.7.1", expected: false},
+		{name: "Release candidate", version: "25.7.r1", minimum: "25.7", expected: true},
+		{name: "Release candidate with suffix", version: "25.7-rc1", minimum: "25.1", expected: true},
+		{name: "Unparsable version", version: "undefined", minimum: "24.1", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := versionAtLeast(tc.version, tc.minimum); got != tc.expected {
+				t.Errorf("versionAtLeast(%q, %q) = %v, expected %v", tc.version, tc.minimum, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDetectPlugins(t *testing.T) {
+	tests := []struct {
+		name     string
+		release  string
+		fault    *opnsensetest.Fault
+		expected map[string][2]string
+	}{
+		{name: "24.7", release: "24.7", expected: map[string][2]string{
+			DnsmasqSubsystem: {"", "25.1"},
+		}},
+		{name: "25.1", release: "25.1", expected: map[string][2]string{}},
+		{name: "25.7", release: "25.7", expected: map[string][2]string{}},
+		{name: "Old release without plugins", release: "25.7", fault: &opnsensetest.Fault{
+			Body: []byte(`{"product":{"product_version":"23.7.12"},"package":[],"plugin":[]}`),
+		}, expected: map[string][2]string{
+			WireguardSubsystem:     {"os-wireguard", ""},
+			KeaSubsystem:           {"", "24.1"},
+			DnsmasqSubsystem:       {"", "25.1"},
+			FirewallRulesSubsystem: {"os-firewall", ""},
+		}},
+		{name: "Unparsable version", release: "25.7", fault: &opnsensetest.Fault{
+			Body: []byte(`{"product":{"product_version":""},"package":[],"plugin":[]}`),
+		}, expected: map[string][2]string{}},
+		{name: "ISC DHCP moved to a plugin", release: "25.7", fault: &opnsensetest.Fault{
+			Body: []byte(`{"product":{"product_version":"26.1"},"package":[],"plugin":[]}`),
+		}, expected: map[string][2]string{
+			DHCPv4Subsystem: {"os-isc-dhcp", ""},
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := opnsensetest.NewServer(t, tc.release)
+			if tc.fault != nil {
+				server.SetFault("firmwareInfo", *tc.fault)
+			}
+
+			collector, err := New(server.Client(t), promslog.NewNopLogger(), "test")
+			if err != nil {
+				t.Fatalf("expected no error when creating collector, got %v", err)
+			}
+			collector.detectPlugins(context.Background())
+
+			registry := prometheus.NewPedanticRegistry()
+			registry.MustRegister(collector)
+			families, gatherErr := registry.Gather()
+			if gatherErr != nil {
+				t.Fatalf("failed to gather metrics: %v", gatherErr)
+			}
+
+			got := make(map[string][2]string)
+			for _, family := range families {
+				if family.GetName() != "opnsense_exporter_collector_auto_disabled_info" {
+					continue
+				}
+				for _, metric := range family.GetMetric() {
+					labels := make(map[string]string)
+					for _, label := range metric.GetLabel() {
+						labels[label.GetName()] = label.GetValue()
+					}
+					got[labels["collector"]] = [2]string{labels["plugin"], labels["min_version"]}
+				}
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("auto disabled collectors = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectorStatus is the result of the last update of a collector.
+// The metrics are only cached for collectors with a refresh interval.
+type collectorStatus struct {
+	metrics     []prometheus.Metric
+	duration    time.Duration
+	success     bool
+	lastSuccess time.Time
+}
+
+// WithRefreshInterval Option
+// refreshes the collector with the given name in the background at the interval.
+// Scrapes are served from the metrics of the last successful refresh.
+func WithRefreshInterval(name string, interval time.Duration) Option {
+	return func(o *Collector) error {
+		for _, collector := range o.collectors {
+			if collector.Name() == name {
+				o.refreshIntervals[name] = interval
+				return nil
+			}
+		}
+		return fmt.Errorf("collector %s not found", name)
+	}
+}
+
+// WithDefaultRefreshInterval Option
+// refreshes the collectors without their own refresh interval in the background at the interval.
+// 0 means these collectors are updated on every scrape.
+func WithDefaultRefreshInterval(interval time.Duration) Option {
+	return func(o *Collector) error {
+		o.defaultRefreshInterval = interval
+		return nil
+	}
+}
+
+// refreshInterval returns the refresh interval of the collector
+// or 0 if the collector is updated on every scrape.
+func (c *Collector) refreshInterval(name string) time.Duration {
+	if interval, ok := c.refreshIntervals[name]; ok {
+		return interval
+	}
+	return c.defaultRefreshInterval
+}
+
+// Start starts the background refresh of the collectors that have a refresh interval
+// and the periodic detection of the installed plugins.
+func (c *Collector) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopPolling = cancel
+
+	if c.pluginDetectionInterval > 0 {
+		c.polling.Add(1)
+		go c.detectPluginsPeriodically(ctx, c.pluginDetectionInterval)
+	}
+
+	for _, collector := range c.collectors {
+		interval := c.refreshInterval(collector.Name())
+		if interval <= 0 {
+			continue
+		}
+
+		c.log.Debug("starting background refresh", "collector", collector.Name(), "interval", interval)
+
+		c.polling.Add(1)
+		go c.poll(ctx, collector, interval)
+	}
+}
+
+// Stop stops the background refresh of the collectors and the plugin detection
+// and waits for the running refreshes.
+func (c *Collector) Stop() {
+	if c.stopPolling == nil {
+		return
+	}
+	c.stopPolling()
+	c.polling.Wait()
+}
+
+// poll refreshes the collector at the interval until the context is done.
+func (c *Collector) poll(ctx context.Context, coll CollectorInstance, interval time.Duration) {
+	defer c.polling.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !c.autoDisabled(coll.Name()) {
+			c.refresh(ctx, coll, interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh updates the collector and caches its metrics when the update succeeded.
+// A refresh never runs longer than the interval.
+func (c *Collector) refresh(ctx context.Context, coll CollectorInstance, interval time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan []prometheus.Metric)
+
+	go func() {
+		var metrics []prometheus.Metric
+		for metric := range ch {
+			metrics = append(metrics, metric)
+		}
+		done <- metrics
+	}()
+
+	status := c.update(ctx, coll, ch)
+	close(ch)
+	metrics := <-done
+
+	if !status.success {
+		return
+	}
+
+	c.statusMutex.Lock()
+	status = c.status[coll.Name()]
+	status.metrics = metrics
+	c.status[coll.Name()] = status
+	c.statusMutex.Unlock()
+}
+
+// collectCached sends the cached metrics and the status of a collector
+// that is refreshed in the background.
+func (c *Collector) collectCached(ch chan<- prometheus.Metric, name string) {
+	c.statusMutex.RLock()
+	status := c.status[name]
+	c.statusMutex.RUnlock()
+
+	for _, metric := range status.metrics {
+		ch <- metric
+	}
+	c.collectStatus(ch, name, status)
+}
@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/promslog"
+)
+
+type countingCollector struct {
+	updates atomic.Int64
+	desc    *prometheus.Desc
+}
+
+func (c *countingCollector) Name() string { return "counting" }
+
+func (c *countingCollector) Register(namespace, instance string, log *slog.Logger) {
+	c.desc = buildPrometheusDesc("counting", "updates", "Number of updates", nil)
+}
+
+func (c *countingCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+
+func (c *countingCollector) Endpoints() []opnsense.EndpointName { return nil }
+
+func (c *countingCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	updates := c.updates.Add(1)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(updates), "test")
+	return nil
+}
+
+func TestRefreshInterval(t *testing.T) {
+	client, err := opnsense.NewClient(options.OPNSenseConfig{Protocol: "http"}, "test", promslog.NewNopLogger())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	collector, err := New(&client, promslog.NewNopLogger(), "test")
+	if err != nil {
+		t.Fatalf("expected no error when creating collector, got %v", err)
+	}
+
+	counting := &countingCollector{}
+	counting.Register(namespace, "test", promslog.NewNopLogger())
+	collector.collectors = []CollectorInstance{counting}
+	collector.updateMutexes[counting.Name()] = &sync.Mutex{}
+	collector.refreshIntervals[counting.Name()] = time.Hour
+
+	collector.Start()
+	defer collector.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for counting.updates.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	for i := 0; i < 3; i++ {
+		count, err := testutil.GatherAndCount(registry, "opnsense_counting_updates")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if count != 1 {
+			t.Errorf("expected the cached metric to be served, got %d series", count)
+		}
+	}
+
+	if updates := counting.updates.Load(); updates != 1 {
+		t.Errorf("expected a single background update, got %d", updates)
+	}
+}
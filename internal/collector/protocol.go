@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -31,8 +32,10 @@ type protocolCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &protocolCollector{
-		subsystem: ProtocolSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &protocolCollector{
+			subsystem: ProtocolSubsystem,
+		}
 	})
 }
 
@@ -117,8 +120,12 @@ func (c *protocolCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.udpDroppedByReason
 }
 
-func (c *protocolCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchProtocolStatistics()
+func (c *protocolCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"protocolStatistics"}
+}
+
+func (c *protocolCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchProtocolStatistics(ctx)
 	if err != nil {
 		return err
 	}
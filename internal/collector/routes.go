@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// routeFamilies holds the address families of the routes.
+var routeFamilies = []string{"ipv4", "ipv6"}
+
+// routeKey holds the labels the routes are counted by.
+type routeKey struct {
+	family     string
+	iface      string
+	static     bool
+	viaGateway bool
+	host       bool
+}
+
+type routesCollector struct {
+	log *slog.Logger
+
+	routes           *prometheus.Desc
+	defaultRoute     *prometheus.Desc
+	defaultRouteInfo *prometheus.Desc
+	routeInfo        *prometheus.Desc
+
+	details bool
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &routesCollector{
+			subsystem: RoutesSubsystem,
+		}
+	})
+}
+
+func (c *routesCollector) Name() string {
+	return c.subsystem
+}
+
+// Configure enables the metric per route.
+func (c *routesCollector) Configure(settings Settings) error {
+	c.details = settings.Details
+	return nil
+}
+
+func (c *routesCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.routes = buildPrometheusDesc(c.subsystem, "entries",
+		"Number of routes by address family, interface and flags",
+		[]string{"family", "interface", "static", "gateway", "host"})
+	c.defaultRoute = buildPrometheusDesc(c.subsystem, "default_route",
+		"Whether a default route of the address family exists (1 = yes, 0 = no)",
+		[]string{"family"})
+	c.defaultRouteInfo = buildPrometheusDesc(c.subsystem, "default_route_info",
+		"Gateway and interface of the default route of the address family, always 1",
+		[]string{"family", "gateway", "interface"})
+	c.routeInfo = buildPrometheusDesc(c.subsystem, "route_info",
+		"Route with its destination, gateway, interface and flags, always 1",
+		[]string{"family", "destination", "gateway", "interface", "flags"})
+}
+
+func (c *routesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.routes
+	ch <- c.defaultRoute
+	ch <- c.defaultRouteInfo
+	ch <- c.routeInfo
+}
+
+func (c *routesCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"routes"}
+}
+
+func (c *routesCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchRoutes(ctx)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[routeKey]int)
+	defaults := make(map[string]bool, len(routeFamilies))
+	for _, route := range data {
+		counts[routeKey{
+			family:     route.Family,
+			iface:      route.Interface,
+			static:     route.Static,
+			viaGateway: route.ViaGateway,
+			host:       route.Host,
+		}]++
+
+		if route.Default {
+			defaults[route.Family] = true
+			ch <- prometheus.MustNewConstMetric(c.defaultRouteInfo, prometheus.GaugeValue, 1,
+				route.Family, route.Gateway, route.Interface, c.instance)
+		}
+
+		if c.details {
+			ch <- prometheus.MustNewConstMetric(c.routeInfo, prometheus.GaugeValue, 1,
+				route.Family, route.Destination, route.Gateway, route.Interface, route.Flags, c.instance)
+		}
+	}
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.routes, prometheus.GaugeValue, float64(count),
+			key.family, key.iface, strconv.FormatBool(key.static), strconv.FormatBool(key.viaGateway),
+			strconv.FormatBool(key.host), c.instance)
+	}
+
+	for _, family := range routeFamilies {
+		value := 0.0
+		if defaults[family] {
+			value = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(c.defaultRoute, prometheus.GaugeValue, value, family, c.instance)
+	}
+
+	return nil
+}
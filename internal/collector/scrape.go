@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scrapeTimeoutHeader is the header Prometheus sets on every scrape request
+// with the scrape timeout of the job in seconds.
+const scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+
+// ScrapeContext derives a context for a scrape request that expires
+// when the Prometheus scrape timeout, reduced by the offset, is reached.
+// Without the header the request context is only cancelled with the request.
+func ScrapeContext(r *http.Request, offset time.Duration) (context.Context, context.CancelFunc, error) {
+	header := r.Header.Get(scrapeTimeoutHeader)
+	if header == "" {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, nil
+	}
+
+	seconds, err := strconv.ParseFloat(header, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s header %q: %w", scrapeTimeoutHeader, header, err)
+	}
+
+	timeout := time.Duration(seconds*float64(time.Second)) - offset
+	if timeout <= 0 {
+		return nil, nil, fmt.Errorf("scrape timeout of %s is not larger than the timeout offset of %s", header+"s", offset)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return ctx, cancel, nil
+}
+
+// scrapeCollector binds a Collector to the context of a single scrape.
+type scrapeCollector struct {
+	collector *Collector
+	ctx       context.Context
+	filter    map[string]bool
+}
+
+// WithContext returns a prometheus.Collector that collects the metrics
+// of the Collector bounded by the given context. When names are given,
+// only the collectors with these names are updated.
+// An error is returned if a name is not an enabled collector.
+func (c *Collector) WithContext(ctx context.Context, names ...string) (prometheus.Collector, error) {
+	filter := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !c.hasCollector(name) {
+			return nil, fmt.Errorf("collector %q is not enabled", name)
+		}
+		filter[name] = true
+	}
+
+	return &scrapeCollector{
+		collector: c,
+		ctx:       ctx,
+		filter:    filter,
+	}, nil
+}
+
+// hasCollector reports whether the collector with the given name is enabled.
+func (c *Collector) hasCollector(name string) bool {
+	for _, collector := range c.collectors {
+		if collector.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *scrapeCollector) Describe(ch chan<- *prometheus.Desc) {
+	s.collector.Describe(ch)
+}
+
+func (s *scrapeCollector) Collect(ch chan<- prometheus.Metric) {
+	s.collector.collect(s.ctx, s.filter, ch)
+}
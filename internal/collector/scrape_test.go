@@ -0,0 +1,191 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+)
+
+func TestScrapeContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		offset      time.Duration
+		expectErr   bool
+		hasDeadline bool
+	}{
+		{
+			name:        "Without header",
+			header:      "",
+			offset:      500 * time.Millisecond,
+			hasDeadline: false,
+		},
+		{
+			name:        "With header",
+			header:      "10",
+			offset:      500 * time.Millisecond,
+			hasDeadline: true,
+		},
+		{
+			name:      "Offset larger than the timeout",
+			header:    "0.5",
+			offset:    time.Second,
+			expectErr: true,
+		},
+		{
+			name:      "Invalid header",
+			header:    "ten",
+			offset:    500 * time.Millisecond,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tc.header != "" {
+				req.Header.Set(scrapeTimeoutHeader, tc.header)
+			}
+
+			ctx, cancel, err := ScrapeContext(req, tc.offset)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if ok != tc.hasDeadline {
+				t.Fatalf("expected deadline %t, got %t", tc.hasDeadline, ok)
+			}
+			if ok && time.Until(deadline) > 9500*time.Millisecond {
+				t.Errorf("expected the deadline to be reduced by the offset, got %s", time.Until(deadline))
+			}
+		})
+	}
+}
+
+func TestWithContextFilter(t *testing.T) {
+	client, err := opnsense.NewClient(options.OPNSenseConfig{Protocol: "http"}, "test", promslog.NewNopLogger())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	collector, err := New(&client, promslog.NewNopLogger(), "test", WithoutCollector(WireguardSubsystem))
+	if err != nil {
+		t.Fatalf("expected no error when creating collector, got %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		filter    []string
+		expectErr bool
+	}{
+		{name: "Without filter"},
+		{name: "Enabled collectors", filter: []string{GatewaysSubsystem, FirmwareSubsystem}},
+		{name: "Disabled collector", filter: []string{WireguardSubsystem}, expectErr: true},
+		{name: "Unknown collector", filter: []string{"unknown"}, expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scrape, err := collector.WithContext(context.Background(), tc.filter...)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if filter := scrape.(*scrapeCollector).filter; len(filter) != len(tc.filter) {
+				t.Errorf("expected %d collectors in the filter, got %d", len(tc.filter), len(filter))
+			}
+		})
+	}
+}
+
+type blockingCollector struct {
+	started chan struct{}
+	release chan struct{}
+	desc    *prometheus.Desc
+}
+
+func (c *blockingCollector) Name() string { return "blocking" }
+
+func (c *blockingCollector) Register(namespace, instance string, log *slog.Logger) {
+	c.desc = buildPrometheusDesc("blocking", "updates", "Number of updates", nil)
+}
+
+func (c *blockingCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+
+func (c *blockingCollector) Endpoints() []opnsense.EndpointName { return nil }
+
+func (c *blockingCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	close(c.started)
+	<-c.release
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1, "test")
+	return nil
+}
+
+func TestConcurrentScrapes(t *testing.T) {
+	client, err := opnsense.NewClient(options.OPNSenseConfig{Protocol: "http"}, "test", promslog.NewNopLogger())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	collector, err := New(&client, promslog.NewNopLogger(), "test")
+	if err != nil {
+		t.Fatalf("expected no error when creating collector, got %v", err)
+	}
+
+	blocking := &blockingCollector{started: make(chan struct{}), release: make(chan struct{})}
+	counting := &countingCollector{}
+	collector.collectors = []CollectorInstance{blocking, counting}
+	for _, coll := range collector.collectors {
+		coll.Register(namespace, "test", promslog.NewNopLogger())
+		collector.updateMutexes[coll.Name()] = &sync.Mutex{}
+	}
+
+	scrape := func(name string) <-chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			ch := make(chan prometheus.Metric)
+			go func() {
+				collector.collect(context.Background(), map[string]bool{name: true}, ch)
+				close(ch)
+			}()
+			for range ch {
+			}
+		}()
+		return done
+	}
+
+	blocked := scrape(blocking.Name())
+	<-blocking.started
+
+	select {
+	case <-scrape(counting.Name()):
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the scrape of another collector not to wait for the blocked scrape")
+	}
+
+	close(blocking.release)
+	<-blocked
+}
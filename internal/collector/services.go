@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -18,8 +19,10 @@ type servicesCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &servicesCollector{
-		subsystem: ServicesSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &servicesCollector{
+			subsystem: ServicesSubsystem,
+		}
 	})
 }
 
@@ -54,8 +57,12 @@ func (c *servicesCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.servicesStopped
 }
 
-func (c *servicesCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	services, err := client.FetchServices()
+func (c *servicesCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"services"}
+}
+
+func (c *servicesCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	services, err := client.FetchServices(ctx)
 	if err != nil {
 		return err
 	}
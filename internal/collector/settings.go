@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Settings holds the collector specific settings of a single collector.
+// Collectors ignore the settings they do not support.
+type Settings struct {
+	// Details enables the optional per-object metrics of the collector, e.g. one metric per DHCP lease.
+	Details bool
+	// Pools holds the address range ("first-last") of the DHCP pool by interface,
+	// for the DHCP servers that do not report their pools through the API.
+	Pools map[string]string
+	// TopN is the number of entries of the optional top lists of the collector. 0 disables them.
+	TopN int
+	// Include and Exclude are regular expressions that select the objects
+	// of the collector that are exposed, e.g. the firewall rules by description.
+	// Empty expressions include all and exclude none.
+	Include string
+	Exclude string
+}
+
+// configurableCollector is implemented by the collectors that have collector specific settings.
+type configurableCollector interface {
+	Configure(settings Settings) error
+}
+
+// WithCollectorSettings Option
+// applies the collector specific settings to the collector with the given name.
+// Settings of known collectors that are disabled are ignored.
+func WithCollectorSettings(name string, settings Settings) Option {
+	return func(o *Collector) error {
+		for _, collector := range o.collectors {
+			if collector.Name() != name {
+				continue
+			}
+			configurable, ok := collector.(configurableCollector)
+			if !ok {
+				return nil
+			}
+			if err := configurable.Configure(settings); err != nil {
+				return fmt.Errorf("invalid settings of collector %s: %w", name, err)
+			}
+			return nil
+		}
+		if _, ok := Defaults()[name]; ok {
+			return nil
+		}
+		return fmt.Errorf("collector %s not found", name)
+	}
+}
+
+// objectFilter selects the objects of a collector by the Include and Exclude expressions of the Settings.
+// The expressions are anchored and must match a whole value.
+type objectFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func newObjectFilter(settings Settings) (objectFilter, error) {
+	var filter objectFilter
+	var err error
+
+	if settings.Include != "" {
+		if filter.include, err = regexp.Compile("^(?:" + settings.Include + ")$"); err != nil {
+			return filter, fmt.Errorf("invalid include expression: %w", err)
+		}
+	}
+	if settings.Exclude != "" {
+		if filter.exclude, err = regexp.Compile("^(?:" + settings.Exclude + ")$"); err != nil {
+			return filter, fmt.Errorf("invalid exclude expression: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// matches reports whether an object is selected by the filter. An object is selected
+// if any of its values matches the include expression and none matches the exclude expression.
+func (f objectFilter) matches(values ...string) bool {
+	included := f.include == nil
+	for _, value := range values {
+		if f.exclude != nil && f.exclude.MatchString(value) {
+			return false
+		}
+		if f.include != nil && f.include.MatchString(value) {
+			included = true
+		}
+	}
+	return included
+}
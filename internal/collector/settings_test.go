@@ -0,0 +1,36 @@
+package collector
+
+import "testing"
+
+func TestObjectFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		values   []string
+		expected bool
+	}{
+		{name: "No expressions", settings: Settings{}, values: []string{"Allow LAN to any"}, expected: true},
+		{name: "Included", settings: Settings{Include: "Block.*"}, values: []string{"Block bogons", "uuid"}, expected: true},
+		{name: "Not included", settings: Settings{Include: "Block.*"}, values: []string{"Allow LAN to any", "uuid"}, expected: false},
+		{name: "Include is anchored", settings: Settings{Include: "Block"}, values: []string{"Block bogons"}, expected: false},
+		{name: "Excluded", settings: Settings{Exclude: ".*bogons"}, values: []string{"Block bogons"}, expected: false},
+		{name: "Exclude wins", settings: Settings{Include: "Block.*", Exclude: ".*bogons"}, values: []string{"Block bogons"}, expected: false},
+		{name: "Second value", settings: Settings{Include: "6f2b.*"}, values: []string{"", "6f2b1c4e"}, expected: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := newObjectFilter(tc.settings)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := filter.matches(tc.values...); got != tc.expected {
+				t.Errorf("matches(%q) = %v, expected %v", tc.values, got, tc.expected)
+			}
+		})
+	}
+
+	if _, err := newObjectFilter(Settings{Include: "("}); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+}
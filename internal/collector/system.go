@@ -0,0 +1,185 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type systemCollector struct {
+	log *slog.Logger
+
+	memoryTotal         *prometheus.Desc
+	memoryUsed          *prometheus.Desc
+	arcSize             *prometheus.Desc
+	swapTotal           *prometheus.Desc
+	swapUsed            *prometheus.Desc
+	filesystemSize      *prometheus.Desc
+	filesystemUsed      *prometheus.Desc
+	filesystemAvailable *prometheus.Desc
+	load1               *prometheus.Desc
+	load5               *prometheus.Desc
+	load15              *prometheus.Desc
+	uptime              *prometheus.Desc
+	bootTime            *prometheus.Desc
+	cpuUtilization      *prometheus.Desc
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &systemCollector{
+			subsystem: SystemSubsystem,
+		}
+	})
+}
+
+func (c *systemCollector) Name() string {
+	return c.subsystem
+}
+
+func (c *systemCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.memoryTotal = buildPrometheusDesc(c.subsystem, "memory_total_bytes",
+		"Total physical memory in bytes", nil)
+
+	c.memoryUsed = buildPrometheusDesc(c.subsystem, "memory_used_bytes",
+		"Used physical memory in bytes", nil)
+
+	c.arcSize = buildPrometheusDesc(c.subsystem, "memory_arc_bytes",
+		"Size of the ZFS ARC in bytes", nil)
+
+	c.swapTotal = buildPrometheusDesc(c.subsystem, "swap_total_bytes",
+		"Size of the swap device in bytes", []string{"device"})
+
+	c.swapUsed = buildPrometheusDesc(c.subsystem, "swap_used_bytes",
+		"Used space of the swap device in bytes", []string{"device"})
+
+	c.filesystemSize = buildPrometheusDesc(c.subsystem, "filesystem_size_bytes",
+		"Size of the filesystem in bytes", []string{"device", "type", "mountpoint"})
+
+	c.filesystemUsed = buildPrometheusDesc(c.subsystem, "filesystem_used_bytes",
+		"Used space of the filesystem in bytes", []string{"device", "type", "mountpoint"})
+
+	c.filesystemAvailable = buildPrometheusDesc(c.subsystem, "filesystem_available_bytes",
+		"Space of the filesystem available to non-root users in bytes", []string{"device", "type", "mountpoint"})
+
+	c.load1 = buildPrometheusDesc(c.subsystem, "load1",
+		"1 minute load average", nil)
+
+	c.load5 = buildPrometheusDesc(c.subsystem, "load5",
+		"5 minute load average", nil)
+
+	c.load15 = buildPrometheusDesc(c.subsystem, "load15",
+		"15 minute load average", nil)
+
+	c.uptime = buildPrometheusDesc(c.subsystem, "uptime_seconds",
+		"Time since the system was booted in seconds", nil)
+
+	c.bootTime = buildPrometheusDesc(c.subsystem, "boot_time_seconds",
+		"Unix timestamp of the system boot", nil)
+
+	c.cpuUtilization = buildPrometheusDesc(c.subsystem, "cpu_utilization_ratio",
+		"Ratio of the CPU time spent in each mode (0-1)", []string{"mode"})
+}
+
+func (c *systemCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.memoryTotal
+	ch <- c.memoryUsed
+	ch <- c.arcSize
+	ch <- c.swapTotal
+	ch <- c.swapUsed
+	ch <- c.filesystemSize
+	ch <- c.filesystemUsed
+	ch <- c.filesystemAvailable
+	ch <- c.load1
+	ch <- c.load5
+	ch <- c.load15
+	ch <- c.uptime
+	ch <- c.bootTime
+	ch <- c.cpuUtilization
+}
+
+func (c *systemCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"systemResources", "systemDisk", "systemSwap", "systemTime", "systemActivity"}
+}
+
+// Update fetches the system endpoints independently, so the metrics of the other
+// endpoints are exposed when one of them fails. The first error is returned
+// and the errors of the other endpoints are logged.
+func (c *systemCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	var firstErr *opnsense.APICallError
+	failed := func(err *opnsense.APICallError) bool {
+		if err == nil {
+			return false
+		}
+		if firstErr == nil {
+			firstErr = err
+		} else {
+			c.log.Error("failed to update", "collector", c.Name(), "endpoint", err.Endpoint, "err", err)
+		}
+		return true
+	}
+
+	if memory, err := client.FetchSystemMemory(ctx); !failed(err) {
+		ch <- prometheus.MustNewConstMetric(c.memoryTotal, prometheus.GaugeValue, memory.Total, c.instance)
+		ch <- prometheus.MustNewConstMetric(c.memoryUsed, prometheus.GaugeValue, memory.Used, c.instance)
+		if memory.ArcSize >= 0 {
+			ch <- prometheus.MustNewConstMetric(c.arcSize, prometheus.GaugeValue, memory.ArcSize, c.instance)
+		}
+	}
+
+	if swaps, err := client.FetchSwap(ctx); !failed(err) {
+		for _, swap := range swaps {
+			ch <- prometheus.MustNewConstMetric(c.swapTotal, prometheus.GaugeValue, swap.Total, swap.Device, c.instance)
+			ch <- prometheus.MustNewConstMetric(c.swapUsed, prometheus.GaugeValue, swap.Used, swap.Device, c.instance)
+		}
+	}
+
+	if filesystems, err := client.FetchFilesystems(ctx); !failed(err) {
+		// df -a may list a mountpoint more than once, only the first entry is exposed
+		seen := make(map[[3]string]bool)
+		for _, fs := range filesystems {
+			key := [3]string{fs.Device, fs.Type, fs.Mountpoint}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			ch <- prometheus.MustNewConstMetric(c.filesystemSize, prometheus.GaugeValue, fs.Size, fs.Device, fs.Type, fs.Mountpoint, c.instance)
+			ch <- prometheus.MustNewConstMetric(c.filesystemUsed, prometheus.GaugeValue, fs.Used, fs.Device, fs.Type, fs.Mountpoint, c.instance)
+			ch <- prometheus.MustNewConstMetric(c.filesystemAvailable, prometheus.GaugeValue, fs.Available, fs.Device, fs.Type, fs.Mountpoint, c.instance)
+		}
+	}
+
+	if systemTime, err := client.FetchSystemTime(ctx); !failed(err) {
+		ch <- prometheus.MustNewConstMetric(c.load1, prometheus.GaugeValue, systemTime.Load[0], c.instance)
+		ch <- prometheus.MustNewConstMetric(c.load5, prometheus.GaugeValue, systemTime.Load[1], c.instance)
+		ch <- prometheus.MustNewConstMetric(c.load15, prometheus.GaugeValue, systemTime.Load[2], c.instance)
+
+		ch <- prometheus.MustNewConstMetric(c.uptime, prometheus.GaugeValue, systemTime.Uptime.Seconds(), c.instance)
+		ch <- prometheus.MustNewConstMetric(c.bootTime, prometheus.GaugeValue, float64(systemTime.BootTime.Unix()), c.instance)
+	}
+
+	if cpu, err := client.FetchCPUUtilization(ctx); !failed(err) {
+		modes := make([]string, 0, len(cpu))
+		for mode := range cpu {
+			modes = append(modes, mode)
+		}
+		sort.Strings(modes)
+		for _, mode := range modes {
+			ch <- prometheus.MustNewConstMetric(c.cpuUtilization, prometheus.GaugeValue, cpu[mode], mode, c.instance)
+		}
+	}
+
+	return firstErr
+}
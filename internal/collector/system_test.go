@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSystemPartialUpdate(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	server.SetFault("systemSwap", opnsensetest.Fault{StatusCode: http.StatusForbidden})
+	c := newUpdateCollector(&systemCollector{subsystem: SystemSubsystem}, server.Client(t))
+
+	for _, name := range []string{
+		"opnsense_system_memory_total_bytes",
+		"opnsense_system_filesystem_size_bytes",
+		"opnsense_system_load1",
+		"opnsense_system_cpu_utilization_ratio",
+	} {
+		if count := testutil.CollectAndCount(c, name); count == 0 {
+			t.Errorf("expected %s to be collected", name)
+		}
+	}
+	if count := testutil.CollectAndCount(c, "opnsense_system_swap_total_bytes"); count != 0 {
+		t.Errorf("expected the swap metrics not to be collected, got %d series", count)
+	}
+	if c.err == nil || c.err.StatusCode != http.StatusForbidden {
+		t.Errorf("expected the error of the swap endpoint, got %v", c.err)
+	}
+}
+
+func TestSystemBootTime(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	client := server.Client(t)
+
+	// the boot time is the same for every scrape
+	expected := time.Date(2024, time.July, 30, 6, 7, 58, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		data, err := client.FetchSystemTime(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !data.BootTime.Equal(expected) {
+			t.Errorf("expected the boot time %s, got %s", expected, data.BootTime)
+		}
+	}
+}
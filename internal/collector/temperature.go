@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type temperatureCollector struct {
+	log *slog.Logger
+
+	celsius *prometheus.Desc
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &temperatureCollector{
+			subsystem: TemperatureSubsystem,
+		}
+	})
+}
+
+func (c *temperatureCollector) Name() string {
+	return c.subsystem
+}
+
+func (c *temperatureCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.celsius = buildPrometheusDesc(c.subsystem, "celsius",
+		"Temperature of the hardware sensor in degrees Celsius by device and type (cpu, zone, ...)",
+		[]string{"device", "type"},
+	)
+}
+
+func (c *temperatureCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.celsius
+}
+
+func (c *temperatureCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"systemTemperature"}
+}
+
+func (c *temperatureCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchTemperatures(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, sensor := range data {
+		ch <- prometheus.MustNewConstMetric(
+			c.celsius,
+			prometheus.GaugeValue,
+			sensor.Celsius,
+			sensor.Device,
+			sensor.Type,
+			c.instance,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense/opnsensetest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTemperatureMalformedValues(t *testing.T) {
+	server := opnsensetest.NewServer(t, "25.7")
+	server.SetFault("systemTemperature", opnsensetest.Fault{Body: []byte(`[
+		{"device":"dev.cpu.0.temperature","device_seq":"0","temperature":"47.0","type":"cpu","type_translated":"CPU"},
+		{"device":"dev.cpu.1.temperature","device_seq":"1","temperature":"N/A","type":"cpu","type_translated":"CPU"}
+	]`)})
+	c := newUpdateCollector(&temperatureCollector{subsystem: TemperatureSubsystem}, server.Client(t))
+
+	if count := testutil.CollectAndCount(c, "opnsense_temperature_celsius"); count != 1 {
+		t.Errorf("expected the temperature of the sensor with a valid reading only, got %d series", count)
+	}
+	if c.err != nil {
+		t.Errorf("expected no error, got %v", c.err)
+	}
+}
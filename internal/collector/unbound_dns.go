@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -11,13 +12,40 @@ type unboundDNSCollector struct {
 	log    *slog.Logger
 	uptime *prometheus.Desc
 
+	queries              *prometheus.Desc
+	receivedQueries      *prometheus.Desc
+	queriesIPRatelimited *prometheus.Desc
+	queriesTimedOut      *prometheus.Desc
+	answers              *prometheus.Desc
+	answersNoData        *prometheus.Desc
+	answersSecure        *prometheus.Desc
+	answersBogus         *prometheus.Desc
+	rrsetBogus           *prometheus.Desc
+	cacheHits            *prometheus.Desc
+	cacheMisses          *prometheus.Desc
+	prefetch             *prometheus.Desc
+	expired              *prometheus.Desc
+	recursiveReplies     *prometheus.Desc
+	recursionTimeAvg     *prometheus.Desc
+	recursionTimeMedian  *prometheus.Desc
+	requestListAvg       *prometheus.Desc
+	requestListMax       *prometheus.Desc
+	requestListCurrent   *prometheus.Desc
+	requestListOverwrite *prometheus.Desc
+	requestListExceeded  *prometheus.Desc
+	memoryCache          *prometheus.Desc
+	memoryModule         *prometheus.Desc
+	cacheEntries         *prometheus.Desc
+
 	subsystem string
 	instance  string
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &unboundDNSCollector{
-		subsystem: UnboundDNSSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &unboundDNSCollector{
+			subsystem: UnboundDNSSubsystem,
+		}
 	})
 }
 
@@ -34,14 +62,138 @@ func (c *unboundDNSCollector) Register(namespace, instanceLabel string, log *slo
 		"Uptime of the unbound DNS service in seconds",
 		nil,
 	)
+	c.queries = buildPrometheusDesc(c.subsystem, "queries_total",
+		"Total number of queries by query type",
+		[]string{"type"},
+	)
+	c.receivedQueries = buildPrometheusDesc(c.subsystem, "received_queries_total",
+		"Total number of queries received by unbound",
+		nil,
+	)
+	c.queriesIPRatelimited = buildPrometheusDesc(c.subsystem, "queries_ip_ratelimited_total",
+		"Total number of queries dropped by the IP rate limit",
+		nil,
+	)
+	c.queriesTimedOut = buildPrometheusDesc(c.subsystem, "queries_timed_out_total",
+		"Total number of queries that timed out in the request list",
+		nil,
+	)
+	c.answers = buildPrometheusDesc(c.subsystem, "answers_total",
+		"Total number of answers by rcode",
+		[]string{"rcode"},
+	)
+	c.answersNoData = buildPrometheusDesc(c.subsystem, "answers_nodata_total",
+		"Total number of NOERROR answers without data",
+		nil,
+	)
+	c.answersSecure = buildPrometheusDesc(c.subsystem, "answers_secure_total",
+		"Total number of answers that were DNSSEC secure",
+		nil,
+	)
+	c.answersBogus = buildPrometheusDesc(c.subsystem, "answers_bogus_total",
+		"Total number of answers that were DNSSEC bogus",
+		nil,
+	)
+	c.rrsetBogus = buildPrometheusDesc(c.subsystem, "rrset_bogus_total",
+		"Total number of rrsets marked bogus by the DNSSEC validator",
+		nil,
+	)
+	c.cacheHits = buildPrometheusDesc(c.subsystem, "cache_hits_total",
+		"Total number of queries answered from the cache",
+		nil,
+	)
+	c.cacheMisses = buildPrometheusDesc(c.subsystem, "cache_misses_total",
+		"Total number of queries that needed recursive processing",
+		nil,
+	)
+	c.prefetch = buildPrometheusDesc(c.subsystem, "prefetch_total",
+		"Total number of cache prefetches",
+		nil,
+	)
+	c.expired = buildPrometheusDesc(c.subsystem, "expired_total",
+		"Total number of queries answered with expired records from the cache",
+		nil,
+	)
+	c.recursiveReplies = buildPrometheusDesc(c.subsystem, "recursive_replies_total",
+		"Total number of replies sent to queries that needed recursive processing",
+		nil,
+	)
+	c.recursionTimeAvg = buildPrometheusDesc(c.subsystem, "recursion_time_seconds_avg",
+		"Average time to answer queries that needed recursive processing in seconds",
+		nil,
+	)
+	c.recursionTimeMedian = buildPrometheusDesc(c.subsystem, "recursion_time_seconds_median",
+		"Median time to answer queries that needed recursive processing in seconds",
+		nil,
+	)
+	c.requestListAvg = buildPrometheusDesc(c.subsystem, "request_list_avg",
+		"Average number of requests in the request list",
+		nil,
+	)
+	c.requestListMax = buildPrometheusDesc(c.subsystem, "request_list_max",
+		"Maximum number of requests in the request list",
+		nil,
+	)
+	c.requestListCurrent = buildPrometheusDesc(c.subsystem, "request_list_current",
+		"Current number of requests in the request list by scope (all, user)",
+		[]string{"scope"},
+	)
+	c.requestListOverwrite = buildPrometheusDesc(c.subsystem, "request_list_overwritten_total",
+		"Total number of requests in the request list that were overwritten by newer requests",
+		nil,
+	)
+	c.requestListExceeded = buildPrometheusDesc(c.subsystem, "request_list_exceeded_total",
+		"Total number of requests dropped because the request list was full",
+		nil,
+	)
+	c.memoryCache = buildPrometheusDesc(c.subsystem, "memory_cache_bytes",
+		"Memory used by the cache in bytes",
+		[]string{"cache"},
+	)
+	c.memoryModule = buildPrometheusDesc(c.subsystem, "memory_module_bytes",
+		"Memory used by the module in bytes",
+		[]string{"module"},
+	)
+	c.cacheEntries = buildPrometheusDesc(c.subsystem, "cache_entries",
+		"Number of entries in the cache",
+		[]string{"cache"},
+	)
 }
 
 func (c *unboundDNSCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.uptime
+	ch <- c.queries
+	ch <- c.receivedQueries
+	ch <- c.queriesIPRatelimited
+	ch <- c.queriesTimedOut
+	ch <- c.answers
+	ch <- c.answersNoData
+	ch <- c.answersSecure
+	ch <- c.answersBogus
+	ch <- c.rrsetBogus
+	ch <- c.cacheHits
+	ch <- c.cacheMisses
+	ch <- c.prefetch
+	ch <- c.expired
+	ch <- c.recursiveReplies
+	ch <- c.recursionTimeAvg
+	ch <- c.recursionTimeMedian
+	ch <- c.requestListAvg
+	ch <- c.requestListMax
+	ch <- c.requestListCurrent
+	ch <- c.requestListOverwrite
+	ch <- c.requestListExceeded
+	ch <- c.memoryCache
+	ch <- c.memoryModule
+	ch <- c.cacheEntries
+}
+
+func (c *unboundDNSCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"unboundDNSStatus"}
 }
 
-func (c *unboundDNSCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchUnboundOverview()
+func (c *unboundDNSCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchUnboundOverview(ctx)
 	if err != nil {
 		return err
 	}
@@ -52,5 +204,51 @@ func (c *unboundDNSCollector) Update(client *opnsense.Client, ch chan<- promethe
 		c.instance,
 	)
 
+	for queryType, value := range data.QueryTypes {
+		ch <- prometheus.MustNewConstMetric(c.queries, prometheus.CounterValue, value, queryType, c.instance)
+	}
+	for rcode, value := range data.AnswerRcodes {
+		ch <- prometheus.MustNewConstMetric(c.answers, prometheus.CounterValue, value, rcode, c.instance)
+	}
+	for cache, value := range data.MemoryCaches {
+		ch <- prometheus.MustNewConstMetric(c.memoryCache, prometheus.GaugeValue, value, cache, c.instance)
+	}
+	for module, value := range data.MemoryModules {
+		ch <- prometheus.MustNewConstMetric(c.memoryModule, prometheus.GaugeValue, value, module, c.instance)
+	}
+	for cache, value := range data.CacheEntries {
+		ch <- prometheus.MustNewConstMetric(c.cacheEntries, prometheus.GaugeValue, value, cache, c.instance)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.requestListCurrent, prometheus.GaugeValue, data.RequestListCurrentAll, "all", c.instance)
+	ch <- prometheus.MustNewConstMetric(c.requestListCurrent, prometheus.GaugeValue, data.RequestListCurrentUser, "user", c.instance)
+
+	for _, metric := range []struct {
+		desc      *prometheus.Desc
+		valueType prometheus.ValueType
+		value     float64
+	}{
+		{c.receivedQueries, prometheus.CounterValue, data.Queries},
+		{c.queriesIPRatelimited, prometheus.CounterValue, data.QueriesIPRatelimited},
+		{c.queriesTimedOut, prometheus.CounterValue, data.QueriesTimedOut},
+		{c.answersNoData, prometheus.CounterValue, data.AnswerNoData},
+		{c.answersSecure, prometheus.CounterValue, data.AnswerSecureTotal},
+		{c.answersBogus, prometheus.CounterValue, data.AnswerBogusTotal},
+		{c.rrsetBogus, prometheus.CounterValue, data.RrsetBogusTotal},
+		{c.cacheHits, prometheus.CounterValue, data.CacheHits},
+		{c.cacheMisses, prometheus.CounterValue, data.CacheMisses},
+		{c.prefetch, prometheus.CounterValue, data.Prefetch},
+		{c.expired, prometheus.CounterValue, data.Expired},
+		{c.recursiveReplies, prometheus.CounterValue, data.RecursiveReplies},
+		{c.recursionTimeAvg, prometheus.GaugeValue, data.RecursionTimeAvg},
+		{c.recursionTimeMedian, prometheus.GaugeValue, data.RecursionTimeMedian},
+		{c.requestListAvg, prometheus.GaugeValue, data.RequestListAvg},
+		{c.requestListMax, prometheus.GaugeValue, data.RequestListMax},
+		{c.requestListOverwrite, prometheus.CounterValue, data.RequestListOverwritten},
+		{c.requestListExceeded, prometheus.CounterValue, data.RequestListExceeded},
+	} {
+		ch <- prometheus.MustNewConstMetric(metric.desc, metric.valueType, metric.value, c.instance)
+	}
+
 	return nil
 }
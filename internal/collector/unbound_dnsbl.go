@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxTopN bounds the top lists, as every entry is a series.
+const maxTopN = 100
+
+type unboundDNSBLCollector struct {
+	log *slog.Logger
+
+	blocklistSize     *prometheus.Desc
+	queries           *prometheus.Desc
+	topBlockedDomains *prometheus.Desc
+	topClients        *prometheus.Desc
+
+	topN int
+
+	subsystem string
+	instance  string
+}
+
+func init() {
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &unboundDNSBLCollector{
+			subsystem: UnboundDNSBLSubsystem,
+		}
+	})
+}
+
+func (c *unboundDNSBLCollector) Name() string {
+	return c.subsystem
+}
+
+// Configure applies the number of entries of the top blocked domains and top clients.
+func (c *unboundDNSBLCollector) Configure(settings Settings) error {
+	if settings.TopN < 0 || settings.TopN > maxTopN {
+		return fmt.Errorf("top_n must be between 0 and %d, got %d", maxTopN, settings.TopN)
+	}
+	c.topN = settings.TopN
+	return nil
+}
+
+func (c *unboundDNSBLCollector) Register(namespace, instanceLabel string, log *slog.Logger) {
+	c.log = log
+	c.instance = instanceLabel
+	c.log.Debug("Registering collector", "collector", c.Name())
+
+	c.blocklistSize = buildPrometheusDesc(c.subsystem, "blocklist_size",
+		"Number of domains on the blocklists",
+		nil,
+	)
+	// a gauge, as the number decreases when queries leave the retention period
+	c.queries = buildPrometheusDesc(c.subsystem, "queries",
+		"Number of queries by result (blocked, passed) within the retention period of the query statistics",
+		[]string{"result"},
+	)
+	c.topBlockedDomains = buildPrometheusDesc(c.subsystem, "top_blocked_domain_queries",
+		"Number of blocked queries of the most blocked domains within the retention period of the query statistics",
+		[]string{"domain"},
+	)
+	c.topClients = buildPrometheusDesc(c.subsystem, "top_client_queries",
+		"Number of queries of the clients with the most queries within the retention period of the query statistics",
+		[]string{"client"},
+	)
+}
+
+func (c *unboundDNSBLCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.blocklistSize
+	ch <- c.queries
+	ch <- c.topBlockedDomains
+	ch <- c.topClients
+}
+
+func (c *unboundDNSBLCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"unboundDNSTotals"}
+}
+
+func (c *unboundDNSBLCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchUnboundBlocklistOverview(ctx, c.topN)
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.blocklistSize, prometheus.GaugeValue, float64(data.BlocklistSize), c.instance)
+	ch <- prometheus.MustNewConstMetric(c.queries, prometheus.GaugeValue, float64(data.Blocked), "blocked", c.instance)
+	ch <- prometheus.MustNewConstMetric(c.queries, prometheus.GaugeValue, float64(data.Passed), "passed", c.instance)
+
+	for _, domain := range topEntries(data.TopBlocked, c.topN) {
+		ch <- prometheus.MustNewConstMetric(c.topBlockedDomains, prometheus.GaugeValue, float64(data.TopBlocked[domain]), domain, c.instance)
+	}
+	for _, clientAddress := range topEntries(data.TopClients, c.topN) {
+		ch <- prometheus.MustNewConstMetric(c.topClients, prometheus.GaugeValue, float64(data.TopClients[clientAddress]), clientAddress, c.instance)
+	}
+
+	return nil
+}
+
+// topEntries returns the keys of the n entries with the highest values,
+// ordered by value and key.
+func topEntries(entries map[string]int, n int) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if entries[keys[i]] != entries[keys[j]] {
+			return entries[keys[i]] > entries[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
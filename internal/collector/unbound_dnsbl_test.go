@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopEntries(t *testing.T) {
+	entries := map[string]int{
+		"ads.example.com":     50,
+		"tracker.example.com": 120,
+		"metrics.example.com": 50,
+		"pixel.example.com":   3,
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		expected []string
+	}{
+		{name: "Disabled", n: 0, expected: []string{}},
+		{name: "Ties ordered by key", n: 3, expected: []string{"tracker.example.com", "ads.example.com", "metrics.example.com"}},
+		{name: "More than available", n: 10, expected: []string{"tracker.example.com", "ads.example.com", "metrics.example.com", "pixel.example.com"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := topEntries(entries, tc.n); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("topEntries(%d) = %v, expected %v", tc.n, got, tc.expected)
+			}
+		})
+	}
+}
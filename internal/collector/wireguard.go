@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/AthennaMind/opnsense-exporter/opnsense"
@@ -20,8 +21,10 @@ type WireguardCollector struct {
 }
 
 func init() {
-	collectorInstances = append(collectorInstances, &WireguardCollector{
-		subsystem: WireguardSubsystem,
+	collectorFactories = append(collectorFactories, func() CollectorInstance {
+		return &WireguardCollector{
+			subsystem: WireguardSubsystem,
+		}
 	})
 }
 
@@ -75,8 +78,12 @@ func (c *WireguardCollector) update(ch chan<- prometheus.Metric, desc *prometheu
 	)
 }
 
-func (c *WireguardCollector) Update(client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
-	data, err := client.FetchWireguardConfig()
+func (c *WireguardCollector) Endpoints() []opnsense.EndpointName {
+	return []opnsense.EndpointName{"wireguardClients"}
+}
+
+func (c *WireguardCollector) Update(ctx context.Context, client *opnsense.Client, ch chan<- prometheus.Metric) *opnsense.APICallError {
+	data, err := client.FetchWireguardConfig(ctx)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,13 @@
 package options
 
-import "github.com/alecthomas/kingpin/v2"
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+)
 
 var (
 	arpTableCollectorDisabled = kingpin.Flag(
@@ -21,7 +28,7 @@ var (
 	).Envar("OPNSENSE_EXPORTER_DISABLE_IPSEC").Default("false").Bool()
 	unboundCollectorDisabled = kingpin.Flag(
 		"exporter.disable-unbound",
-		"Disable the scraping of Unbound service",
+		"Disable the scraping of Unbound service (the unbound_dns and unbound_dnsbl collectors)",
 	).Envar("OPNSENSE_EXPORTER_DISABLE_UNBOUND").Default("false").Bool()
 	openVPNCollectorDisabled = kingpin.Flag(
 		"exporter.disable-openvpn",
@@ -29,14 +36,140 @@ var (
 	).Envar("OPNSENSE_EXPORTER_DISABLE_OPENVPN").Default("false").Bool()
 	firewallCollectorDisabled = kingpin.Flag(
 		"exporter.disable-firewall",
-		"Disable the scraping of the firewall (pf) metrics",
+		"Disable the scraping of the firewall (pf) metrics (the firewall, pf, firewall_rules and firewall_log collectors)",
 	).Envar("OPNSENSE_EXPORTER_DISABLE_FIREWALL").Default("false").Bool()
 	firmwareCollectorDisabled = kingpin.Flag(
 		"exporter.disable-firmware",
 		"Disable the scraping of the firmware metrics",
 	).Envar("OPNSENSE_EXPORTER_DISABLE_FIRMWARE").Default("false").Bool()
+
+	dhcpv4LeaseDetails = kingpin.Flag(
+		"collector.dhcpv4.lease-details",
+		"Expose a metric per DHCPv4 lease of the ISC DHCP server with the address, mac and hostname of the client",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_DHCPV4_LEASE_DETAILS").Default("false").Bool()
+	keaLeaseDetails = kingpin.Flag(
+		"collector.kea.lease-details",
+		"Expose a metric per Kea DHCP lease with the address, mac and hostname of the client",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_KEA_LEASE_DETAILS").Default("false").Bool()
+	dnsmasqLeaseDetails = kingpin.Flag(
+		"collector.dnsmasq.lease-details",
+		"Expose a metric per Dnsmasq DHCP lease with the address, mac and hostname of the client",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_DNSMASQ_LEASE_DETAILS").Default("false").Bool()
+	routesDetails = kingpin.Flag(
+		"collector.routes.details",
+		"Expose a metric per route with the destination, gateway and interface",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_ROUTES_DETAILS").Default("false").Bool()
+	ndpDetails = kingpin.Flag(
+		"collector.ndp.details",
+		"Expose a metric per NDP entry with the ip, mac, interface and manufacturer",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_NDP_DETAILS").Default("false").Bool()
+
+	unboundDNSBLTopN = kingpin.Flag(
+		"collector.unbound_dnsbl.top-n",
+		"Number of the most blocked domains and the most active clients to expose. 0 disables the top lists.",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_UNBOUND_DNSBL_TOP_N").Default("0").Int()
+	firewallLogTopN = kingpin.Flag(
+		"collector.firewall_log.top-n",
+		"Number of the most blocked source addresses and destination ports to expose. 0 disables the top lists.",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_FIREWALL_LOG_TOP_N").Default("0").Int()
+
+	firewallRulesInclude = kingpin.Flag(
+		"collector.firewall_rules.include",
+		"Regular expression of the descriptions or UUIDs of the firewall rules to expose. Empty exposes all rules.",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_FIREWALL_RULES_INCLUDE").Default("").String()
+	firewallRulesExclude = kingpin.Flag(
+		"collector.firewall_rules.exclude",
+		"Regular expression of the descriptions or UUIDs of the firewall rules to leave out.",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_FIREWALL_RULES_EXCLUDE").Default("").String()
 )
 
+// collectorFlag holds the state of a --[no-]collector.<name> flag.
+type collectorFlag struct {
+	enabled   *bool
+	setByUser bool
+	envar     string
+}
+
+// collectorFlags holds the --[no-]collector.<name> flags by collector name.
+var collectorFlags = make(map[string]*collectorFlag)
+
+// RegisterCollectorFlags adds a --[no-]collector.<name> flag for every collector,
+// with the default state given by the defaults map keyed by the collector name.
+// It must be called before Init.
+func RegisterCollectorFlags(defaults map[string]bool) {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		state := "enabled"
+		if !defaults[name] {
+			state = "disabled"
+		}
+
+		f := &collectorFlag{
+			envar: "OPNSENSE_EXPORTER_COLLECTOR_" + strings.ToUpper(name),
+		}
+		f.enabled = kingpin.Flag(
+			"collector."+name,
+			fmt.Sprintf("Enable the %s collector (default: %s).", name, state),
+		).Envar(f.envar).Default(fmt.Sprint(defaults[name])).IsSetByUser(&f.setByUser).Bool()
+
+		collectorFlags[name] = f
+	}
+}
+
+// CollectorFlags returns the per-collector settings from the --[no-]collector.<name>
+// flags and the legacy --exporter.disable-* flags, keyed by the collector name.
+// Collectors that are explicitly enabled or disabled by a flag or environment
+// variable and collectors that are disabled by default are returned.
+func CollectorFlags() map[string]CollectorConfig {
+	collectors := make(map[string]CollectorConfig)
+
+	for name, f := range collectorFlags {
+		_, fromEnv := os.LookupEnv(f.envar)
+		if f.setByUser || fromEnv || !*f.enabled {
+			enabled := *f.enabled
+			collectors[name] = CollectorConfig{Enabled: &enabled}
+		}
+	}
+
+	// The legacy flags can only disable collectors and take precedence.
+	for name, collector := range CollectorsSwitches().Collectors() {
+		collectors[name] = collector
+	}
+
+	return collectors
+}
+
+// detailsFlags returns the --collector.<name>.lease-details and --collector.<name>.details flags by collector name.
+func detailsFlags() map[string]bool {
+	return map[string]bool{
+		"dhcpv4":  *dhcpv4LeaseDetails,
+		"kea":     *keaLeaseDetails,
+		"dnsmasq": *dnsmasqLeaseDetails,
+		"routes":  *routesDetails,
+		"ndp":     *ndpDetails,
+	}
+}
+
+// topNFlags returns the --collector.<name>.top-n flags by collector name.
+func topNFlags() map[string]int {
+	return map[string]int{
+		"unbound_dnsbl": *unboundDNSBLTopN,
+		"firewall_log":  *firewallLogTopN,
+	}
+}
+
+// filterFlags returns the --collector.<name>.include and --collector.<name>.exclude flags by collector name.
+func filterFlags() map[string][2]string {
+	return map[string][2]string{
+		"firewall_rules": {*firewallRulesInclude, *firewallRulesExclude},
+	}
+}
+
 // CollectorsDisableSwitch hold the enabled/disabled state of the collectors
 type CollectorsDisableSwitch struct {
 	ARP       bool
@@ -62,3 +195,31 @@ func CollectorsSwitches() CollectorsDisableSwitch {
 		Firmware:  !*firmwareCollectorDisabled,
 	}
 }
+
+// Collectors returns the disabled collectors as per-collector settings
+// keyed by the collector name. The switches can only disable collectors,
+// so collectors that are not disabled are not returned.
+func (s CollectorsDisableSwitch) Collectors() map[string]CollectorConfig {
+	switches := map[string]bool{
+		"arp_table":      s.ARP,
+		"cron":           s.Cron,
+		"wireguard":      s.Wireguard,
+		"ipsec":          s.IPsec,
+		"unbound_dns":    s.Unbound,
+		"unbound_dnsbl":  s.Unbound,
+		"openvpn":        s.OpenVPN,
+		"firewall":       s.Firewall,
+		"pf":             s.Firewall,
+		"firewall_rules": s.Firewall,
+		"firewall_log":   s.Firewall,
+		"firmware":       s.Firmware,
+	}
+
+	collectors := make(map[string]CollectorConfig, len(switches))
+	for name, enabled := range switches {
+		if !enabled {
+			collectors[name] = CollectorConfig{Enabled: &enabled}
+		}
+	}
+	return collectors
+}
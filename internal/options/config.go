@@ -0,0 +1,127 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+	"go.yaml.in/yaml/v2"
+)
+
+var ConfigFile = kingpin.Flag(
+	"config.file",
+	"Path to a YAML configuration file for the OPNsense target. "+
+		"Values in the file take precedence over the flags. The file is re-read on SIGHUP and POST /-/reload.",
+).Envar("OPNSENSE_EXPORTER_CONFIG_FILE").Default("").String()
+
+// SingleTargetConfigured reports whether a single OPNsense target is configured
+// by the flags or the config file, to be exposed on the metrics path.
+func SingleTargetConfigured() bool {
+	return *opnsenseAPI != "" || *ConfigFile != ""
+}
+
+// Load assembles the configuration of the single OPNsense target.
+// The flags are used as defaults and every value set in the config file overrides them.
+// The config file has the same layout as an entry of the probe targets file.
+func Load() (*TargetConfig, error) {
+	conf := TargetConfig{
+		OPNSenseConfig: OPNSenseConfig{
+			Protocol:  *opnsenseProtocol,
+			Host:      *opnsenseAPI,
+			APIKey:    *opnsenseAPIKey,
+			APISecret: *opnsenseAPISecret,
+			Insecure:  *opnsenseInsecure,
+			TLS: TLSConfig{
+				CAFile:     *opnsenseTLSCAFile,
+				CertFile:   *opnsenseTLSCertFile,
+				KeyFile:    *opnsenseTLSKeyFile,
+				ServerName: *opnsenseTLSServerName,
+				MinVersion: *opnsenseTLSMinVersion,
+			},
+		},
+		APIKeyFile:       os.Getenv("OPS_API_KEY_FILE"),
+		APISecretFile:    os.Getenv("OPS_API_SECRET_FILE"),
+		InstanceLabel:    *InstanceLabel,
+		CollectorTimeout: *collectorTimeout,
+		RefreshInterval:  *refreshInterval,
+
+		PluginDetectionInterval: *pluginDetectionInterval,
+	}
+
+	if *ConfigFile != "" {
+		content, err := os.ReadFile(*ConfigFile)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to read config file"), err)
+		}
+		if err := yaml.UnmarshalStrict(content, &conf); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to parse config file"), err)
+		}
+	}
+
+	// the collector switches from the flags apply only
+	// to the collectors that are not enabled or disabled in the config file
+	for name, collector := range CollectorFlags() {
+		if conf.Collectors == nil {
+			conf.Collectors = make(map[string]CollectorConfig)
+		}
+		fileCollector, ok := conf.Collectors[name]
+		if ok && fileCollector.Enabled != nil {
+			continue
+		}
+		fileCollector.Enabled = collector.Enabled
+		conf.Collectors[name] = fileCollector
+	}
+
+	for name, details := range detailsFlags() {
+		if !details {
+			continue
+		}
+		if conf.Collectors == nil {
+			conf.Collectors = make(map[string]CollectorConfig)
+		}
+		collector := conf.Collectors[name]
+		collector.Details = true
+		conf.Collectors[name] = collector
+	}
+
+	// the top-n flags apply only to the collectors without top_n in the config file
+	for name, topN := range topNFlags() {
+		if topN == 0 {
+			continue
+		}
+		if conf.Collectors == nil {
+			conf.Collectors = make(map[string]CollectorConfig)
+		}
+		collector := conf.Collectors[name]
+		if collector.TopN == 0 {
+			collector.TopN = topN
+		}
+		conf.Collectors[name] = collector
+	}
+
+	// the filter flags apply only to the collectors without include and exclude in the config file
+	for name, filter := range filterFlags() {
+		if filter[0] == "" && filter[1] == "" {
+			continue
+		}
+		if conf.Collectors == nil {
+			conf.Collectors = make(map[string]CollectorConfig)
+		}
+		collector := conf.Collectors[name]
+		if collector.Include == "" && collector.Exclude == "" {
+			collector.Include, collector.Exclude = filter[0], filter[1]
+		}
+		conf.Collectors[name] = collector
+	}
+
+	if conf.InstanceLabel == "" {
+		return nil, fmt.Errorf("exporter.instance-label or instance_label in the config file must be set")
+	}
+
+	if err := conf.resolve(conf.InstanceLabel); err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
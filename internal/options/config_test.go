@@ -0,0 +1,81 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+protocol: https
+address: fw.example.com
+api_key: key
+api_secret: secret
+instance_label: fw1
+collectors:
+  wireguard:
+    enabled: false
+`
+	if err := os.WriteFile(configFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	*ConfigFile = configFile
+	*opnsenseProtocol = "http"
+	t.Cleanup(func() {
+		*ConfigFile = ""
+		*opnsenseProtocol = ""
+	})
+
+	conf, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if conf.Protocol != "https" {
+		t.Errorf("expected the config file to override the protocol flag, got %q", conf.Protocol)
+	}
+	if conf.InstanceLabel != "fw1" {
+		t.Errorf("expected instance label fw1, got %q", conf.InstanceLabel)
+	}
+	if disabled := conf.DisabledCollectors(); len(disabled) != 1 || disabled[0] != "wireguard" {
+		t.Errorf("expected only wireguard to be disabled, got %v", disabled)
+	}
+
+	if err := os.WriteFile(configFile, []byte("protocol: ftp\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Errorf("expected invalid config error, got nil")
+	}
+}
+
+func TestCollectorsDisableSwitch(t *testing.T) {
+	switches := CollectorsDisableSwitch{ARP: true, Cron: true, Wireguard: true, IPsec: true, OpenVPN: true, Firewall: true, Firmware: true}
+
+	collectors := switches.Collectors()
+	if len(collectors) != 2 {
+		t.Errorf("expected two disabled collectors, got %v", collectors)
+	}
+	for _, name := range []string{"unbound_dns", "unbound_dnsbl"} {
+		if collector, ok := collectors[name]; !ok || *collector.Enabled {
+			t.Errorf("expected the %s collector to be disabled by the unbound switch", name)
+		}
+	}
+}
+
+func TestCollectorsDisableSwitchFirewall(t *testing.T) {
+	switches := CollectorsDisableSwitch{ARP: true, Cron: true, Wireguard: true, IPsec: true, Unbound: true, OpenVPN: true, Firmware: true}
+
+	collectors := switches.Collectors()
+	if len(collectors) != 4 {
+		t.Errorf("expected four disabled collectors, got %v", collectors)
+	}
+	for _, name := range []string{"firewall", "pf", "firewall_rules", "firewall_log"} {
+		if collector, ok := collectors[name]; !ok || *collector.Enabled {
+			t.Errorf("expected the %s collector to be disabled by the firewall switch", name)
+		}
+	}
+}
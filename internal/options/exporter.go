@@ -23,7 +23,27 @@ var (
 		"Label to use to identify the instance in every metric. "+
 			"If you have multiple instances of the exporter, you can differentiate them by using "+
 			"different value in this flag, that represents the instance of the target OPNsense.",
-	).Envar("OPNSENSE_EXPORTER_INSTANCE_LABEL").Required().String()
+	).Envar("OPNSENSE_EXPORTER_INSTANCE_LABEL").Default("").String()
+	TimeoutOffset = kingpin.Flag(
+		"exporter.timeout-offset",
+		"Offset to subtract from the Prometheus scrape timeout (X-Prometheus-Scrape-Timeout-Seconds header), "+
+			"so the exporter can answer before Prometheus gives up on the scrape.",
+	).Envar("OPNSENSE_EXPORTER_TIMEOUT_OFFSET").Default("500ms").Duration()
+	collectorTimeout = kingpin.Flag(
+		"exporter.collector-timeout",
+		"Timeout for the update of every collector. A collector that misses it is reported as failed. "+
+			"0 means the collectors are only bounded by the scrape timeout.",
+	).Envar("OPNSENSE_EXPORTER_COLLECTOR_TIMEOUT").Default("0s").Duration()
+	refreshInterval = kingpin.Flag(
+		"exporter.refresh-interval",
+		"Refresh the collectors in the background at this interval and serve the scrapes from the cached results. "+
+			"0 means the collectors are updated on every scrape.",
+	).Envar("OPNSENSE_EXPORTER_REFRESH_INTERVAL").Default("0s").Duration()
+	pluginDetectionInterval = kingpin.Flag(
+		"exporter.plugin-detection-interval",
+		"Interval to query the installed OPNsense plugins and skip the collectors whose plugin is missing. "+
+			"Collectors that are explicitly enabled are never skipped. 0 disables the detection.",
+	).Envar("OPNSENSE_EXPORTER_PLUGIN_DETECTION_INTERVAL").Default("1h").Duration()
 
 	WebConfig = kingpinflag.AddFlags(kingpin.CommandLine, ":8080")
 )
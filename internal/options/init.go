@@ -7,9 +7,23 @@ import (
 	"github.com/prometheus/common/promslog/flag"
 )
 
-func Init() {
+const (
+	// ServeCommand serves the metrics of the OPNsense targets. It is the default command.
+	ServeCommand = "serve"
+	// CheckCommand calls every OPNsense API endpoint once and reports the failures.
+	CheckCommand = "check"
+)
+
+func init() {
+	kingpin.Command(ServeCommand, "Serve the metrics of the OPNsense targets.").Default()
+	kingpin.Command(CheckCommand, "Call every OPNsense API endpoint of the configured targets once "+
+		"and report the status, latency and missing privileges. Exits non-zero on failures.")
+}
+
+// Init parses the command line and returns the selected command.
+func Init() string {
 	flag.AddFlags(kingpin.CommandLine, PromLogConfig)
 	kingpin.CommandLine.UsageWriter(os.Stdout)
 	kingpin.HelpFlag.Short('h')
-	kingpin.Parse()
+	return kingpin.Parse()
 }
@@ -2,23 +2,23 @@ package options
 
 import (
 	"bufio"
-	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/common/config"
 )
 
 var (
 	opnsenseProtocol = kingpin.Flag(
 		"opnsense.protocol",
 		"Protocol to use to connect to OPNsense API. One of: [http, https]",
-	).Envar("OPNSENSE_EXPORTER_OPS_PROTOCOL").Required().String()
+	).Envar("OPNSENSE_EXPORTER_OPS_PROTOCOL").Default("").String()
 	opnsenseAPI = kingpin.Flag(
 		"opnsense.address",
-		"Hostname or IP address of OPNsense API",
-	).Envar("OPNSENSE_EXPORTER_OPS_API").Required().String()
+		"Hostname or IP address of OPNsense API. Required unless only the /probe endpoint is used.",
+	).Envar("OPNSENSE_EXPORTER_OPS_API").Default("").String()
 	opnsenseAPIKey = kingpin.Flag(
 		"opnsense.api-key",
 		"API key to use to connect to OPNsense API. This flag/ENV or the OPS_API_KEY_FILE my be set.",
@@ -31,6 +31,27 @@ var (
 		"opnsense.insecure",
 		"Disable TLS certificate verification",
 	).Envar("OPNSENSE_EXPORTER_OPS_INSECURE").Default("false").Bool()
+	opnsenseTLSCAFile = kingpin.Flag(
+		"opnsense.tls.ca-file",
+		"Path to the CA certificate to verify the OPNsense API certificate with, instead of the system CAs. "+
+			"The file is reloaded when it changes.",
+	).Envar("OPNSENSE_EXPORTER_OPS_TLS_CA_FILE").Default("").String()
+	opnsenseTLSCertFile = kingpin.Flag(
+		"opnsense.tls.cert-file",
+		"Path to the client certificate to connect to the OPNsense API with (mTLS). The file is reloaded when it changes.",
+	).Envar("OPNSENSE_EXPORTER_OPS_TLS_CERT_FILE").Default("").String()
+	opnsenseTLSKeyFile = kingpin.Flag(
+		"opnsense.tls.key-file",
+		"Path to the key of the client certificate. The file is reloaded when it changes.",
+	).Envar("OPNSENSE_EXPORTER_OPS_TLS_KEY_FILE").Default("").String()
+	opnsenseTLSServerName = kingpin.Flag(
+		"opnsense.tls.server-name",
+		"Server name to verify the OPNsense API certificate against, e.g. when connecting through an IP address.",
+	).Envar("OPNSENSE_EXPORTER_OPS_TLS_SERVER_NAME").Default("").String()
+	opnsenseTLSMinVersion = kingpin.Flag(
+		"opnsense.tls.min-version",
+		"Minimum TLS version to connect to the OPNsense API with. One of: [TLS10, TLS11, TLS12, TLS13]",
+	).Envar("OPNSENSE_EXPORTER_OPS_TLS_MIN_VERSION").Default("").String()
 )
 
 // ReadFirstLine opens a file and reads its first line.
@@ -54,47 +75,36 @@ func getLineFromFile(filePath string) (string, error) {
 	return "", nil
 }
 
-func opsAPISecret() (string, error) {
-	if env, ok := os.LookupEnv("OPS_API_SECRET_FILE"); ok {
-		apiKey, err := getLineFromFile(env)
-		if err != nil {
-			return "", errors.Join(fmt.Errorf("failed to read OPS_API_SECRET_FILE"), err)
-		}
-		if len(apiKey) > 0 {
-			return apiKey, nil
-		}
-	}
-	if *opnsenseAPIKey == "" {
-		return "", fmt.Errorf("opnsense.api-secret or OPS_API_SECRET_FILE must be set")
-	}
-
-	return *opnsenseAPISecret, nil
+// TLSConfig holds the TLS settings for the connection to the OPNsense API.
+type TLSConfig struct {
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name"`
+	MinVersion string `yaml:"min_version"`
 }
 
-func opsAPIKey() (string, error) {
-	if env, ok := os.LookupEnv("OPS_API_KEY_FILE"); ok {
-		apiSecret, err := getLineFromFile(env)
-		if err != nil {
-			return "", errors.Join(fmt.Errorf("failed to read OPS_API_KEY_FILE"), err)
-		}
-		if len(apiSecret) > 0 {
-			return apiSecret, nil
-		}
+// Validate checks if the TLS configuration is valid.
+func (c *TLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("tls cert-file and key-file must be set together")
 	}
-	if *opnsenseAPISecret == "" {
-		return "", fmt.Errorf("opnsense.api-key or OPS_API_KEY_FILE must be set")
+	if c.MinVersion != "" {
+		if _, ok := config.TLSVersions[c.MinVersion]; !ok {
+			return fmt.Errorf("tls min-version must be one of: [TLS10, TLS11, TLS12, TLS13]")
+		}
 	}
-
-	return *opnsenseAPIKey, nil
+	return nil
 }
 
 // OPNSenseConfig holds the configuration for the OPNsense API.
 type OPNSenseConfig struct {
-	Protocol  string
-	Host      string
-	APIKey    string
-	APISecret string
-	Insecure  bool
+	Protocol  string    `yaml:"protocol"`
+	Host      string    `yaml:"address"`
+	APIKey    string    `yaml:"api_key"`
+	APISecret string    `yaml:"api_secret"`
+	Insecure  bool      `yaml:"insecure"`
+	TLS       TLSConfig `yaml:"tls_config"`
 }
 
 // Validate checks if the configuration is valid.
@@ -112,29 +122,5 @@ func (c *OPNSenseConfig) Validate() error {
 	if c.APISecret == "" {
 		return fmt.Errorf("api-secret must be set")
 	}
-	return nil
-}
-
-func OPNSense() (*OPNSenseConfig, error) {
-	apiKey, err := opsAPIKey()
-	if err != nil {
-		return nil, err
-	}
-	apiSecret, err := opsAPISecret()
-	if err != nil {
-		return nil, err
-	}
-	conf := &OPNSenseConfig{
-		Protocol:  strings.TrimSpace(*opnsenseProtocol),
-		Host:      strings.TrimSpace(*opnsenseAPI),
-		APIKey:    apiKey,
-		APISecret: apiSecret,
-		Insecure:  *opnsenseInsecure,
-	}
-
-	if err := conf.Validate(); err != nil {
-		return nil, err
-	}
-
-	return conf, nil
+	return c.TLS.Validate()
 }
@@ -28,3 +28,31 @@ func TestOPNSenseConfig(t *testing.T) {
 		t.Errorf("expected no error, got %v", err)
 	}
 }
+
+func TestTLSConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      TLSConfig
+		expectErr bool
+	}{
+		{name: "Empty", conf: TLSConfig{}},
+		{name: "CA and server name", conf: TLSConfig{CAFile: "ca.pem", ServerName: "ops.example.com"}},
+		{name: "Client certificate", conf: TLSConfig{CertFile: "client.pem", KeyFile: "client-key.pem"}},
+		{name: "Certificate without key", conf: TLSConfig{CertFile: "client.pem"}, expectErr: true},
+		{name: "Key without certificate", conf: TLSConfig{KeyFile: "client-key.pem"}, expectErr: true},
+		{name: "Min version", conf: TLSConfig{MinVersion: "TLS12"}},
+		{name: "Invalid min version", conf: TLSConfig{MinVersion: "SSL3"}, expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.conf.Validate()
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
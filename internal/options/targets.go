@@ -0,0 +1,168 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"go.yaml.in/yaml/v2"
+)
+
+var ProbeTargetsFile = kingpin.Flag(
+	"probe.targets-file",
+	"Path to a YAML file with the OPNsense targets that can be scraped through the /probe endpoint.",
+).Envar("OPNSENSE_EXPORTER_PROBE_TARGETS_FILE").Default("").String()
+
+// CollectorConfig holds the settings of a single collector.
+type CollectorConfig struct {
+	Enabled         *bool         `yaml:"enabled"`
+	Timeout         time.Duration `yaml:"timeout"`
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	// Details enables the optional per-object metrics of the collector.
+	Details bool `yaml:"details"`
+	// Pools holds the address range ("first-last") of the DHCP pool by interface.
+	Pools map[string]string `yaml:"pools"`
+	// TopN is the number of entries of the optional top lists of the collector.
+	TopN int `yaml:"top_n"`
+	// Include and Exclude are regular expressions that select the objects of the collector.
+	Include string `yaml:"include"`
+	Exclude string `yaml:"exclude"`
+}
+
+// TargetConfig holds the settings of a single OPNsense firewall
+// that can be scraped through the /probe endpoint.
+type TargetConfig struct {
+	OPNSenseConfig   `yaml:",inline"`
+	APIKeyFile       string        `yaml:"api_key_file"`
+	APISecretFile    string        `yaml:"api_secret_file"`
+	InstanceLabel    string        `yaml:"instance_label"`
+	CollectorTimeout time.Duration `yaml:"collector_timeout"`
+	RefreshInterval  time.Duration `yaml:"refresh_interval"`
+	// PluginDetectionInterval is the interval of the detection of the installed
+	// plugins that disables collectors whose plugin is missing. 0 disables it.
+	PluginDetectionInterval time.Duration              `yaml:"plugin_detection_interval"`
+	Collectors              map[string]CollectorConfig `yaml:"collectors"`
+}
+
+// DisabledCollectors returns the names of the collectors
+// that are explicitly disabled for the target.
+func (t *TargetConfig) DisabledCollectors() []string {
+	var disabled []string
+	for name, collector := range t.Collectors {
+		if collector.Enabled != nil && !*collector.Enabled {
+			disabled = append(disabled, name)
+		}
+	}
+	return disabled
+}
+
+// EnabledCollectors returns the names of the collectors
+// that are explicitly enabled for the target.
+func (t *TargetConfig) EnabledCollectors() []string {
+	var enabled []string
+	for name, collector := range t.Collectors {
+		if collector.Enabled != nil && *collector.Enabled {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled
+}
+
+// CollectorTimeouts returns the timeouts of the collectors
+// that have their own timeout set, keyed by the collector name.
+func (t *TargetConfig) CollectorTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for name, collector := range t.Collectors {
+		if collector.Timeout > 0 {
+			timeouts[name] = collector.Timeout
+		}
+	}
+	return timeouts
+}
+
+// CollectorRefreshIntervals returns the refresh intervals of the collectors
+// that have their own refresh interval set, keyed by the collector name.
+func (t *TargetConfig) CollectorRefreshIntervals() map[string]time.Duration {
+	intervals := make(map[string]time.Duration)
+	for name, collector := range t.Collectors {
+		if collector.RefreshInterval > 0 {
+			intervals[name] = collector.RefreshInterval
+		}
+	}
+	return intervals
+}
+
+// TargetsConfig holds all targets from the targets file by name.
+type TargetsConfig struct {
+	Targets map[string]TargetConfig `yaml:"targets"`
+}
+
+// resolve reads the api key and secret files of the target, if set,
+// defaults the instance label to the target name and validates the result.
+func (t *TargetConfig) resolve(name string) error {
+	if t.APIKeyFile != "" {
+		apiKey, err := getLineFromFile(t.APIKeyFile)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to read api_key_file"), err)
+		}
+		if len(apiKey) > 0 {
+			t.APIKey = apiKey
+		}
+	}
+	if t.APISecretFile != "" {
+		apiSecret, err := getLineFromFile(t.APISecretFile)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to read api_secret_file"), err)
+		}
+		if len(apiSecret) > 0 {
+			t.APISecret = apiSecret
+		}
+	}
+
+	t.Protocol = strings.TrimSpace(t.Protocol)
+	t.Host = strings.TrimSpace(t.Host)
+
+	if t.InstanceLabel == "" {
+		t.InstanceLabel = name
+	}
+
+	return t.Validate()
+}
+
+// LoadTargets reads and validates the targets file.
+func LoadTargets(path string) (*TargetsConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to read targets file"), err)
+	}
+
+	var conf TargetsConfig
+	if err := yaml.UnmarshalStrict(content, &conf); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to parse targets file"), err)
+	}
+
+	if len(conf.Targets) == 0 {
+		return nil, fmt.Errorf("targets file %s contains no targets", path)
+	}
+
+	for name, target := range conf.Targets {
+		if target.CollectorTimeout == 0 {
+			target.CollectorTimeout = *collectorTimeout
+		}
+		if target.RefreshInterval == 0 {
+			target.RefreshInterval = *refreshInterval
+		}
+		if target.PluginDetectionInterval == 0 {
+			target.PluginDetectionInterval = *pluginDetectionInterval
+		}
+		if err := target.resolve(name); err != nil {
+			return nil, errors.Join(fmt.Errorf("invalid target %s", name), err)
+		}
+		conf.Targets[name] = target
+	}
+
+	return &conf, nil
+}
@@ -0,0 +1,68 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTargets(t *testing.T) {
+	dir := t.TempDir()
+
+	secretFile := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretFile, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	targetsFile := filepath.Join(dir, "targets.yaml")
+	content := `
+targets:
+  office:
+    protocol: https
+    address: 10.0.0.1
+    api_key: key
+    api_secret_file: ` + secretFile + `
+    collectors:
+      wireguard:
+        enabled: false
+      arp_table:
+        enabled: true
+  datacenter:
+    protocol: http
+    address: fw.example.com
+    api_key: key
+    api_secret: secret
+    instance_label: dc1
+`
+	if err := os.WriteFile(targetsFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := LoadTargets(targetsFile)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	office := conf.Targets["office"]
+	if office.APISecret != "file-secret" {
+		t.Errorf("expected api secret to be read from file, got %q", office.APISecret)
+	}
+	if office.InstanceLabel != "office" {
+		t.Errorf("expected instance label to default to the target name, got %q", office.InstanceLabel)
+	}
+	if disabled := office.DisabledCollectors(); len(disabled) != 1 || disabled[0] != "wireguard" {
+		t.Errorf("expected only wireguard to be disabled, got %v", disabled)
+	}
+
+	if conf.Targets["datacenter"].InstanceLabel != "dc1" {
+		t.Errorf("expected instance label dc1, got %q", conf.Targets["datacenter"].InstanceLabel)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("targets:\n  fw:\n    protocol: ftp\n    address: fw\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTargets(invalid); err == nil {
+		t.Errorf("expected invalid target error, got nil")
+	}
+}
@@ -0,0 +1,84 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/collector"
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler serves the metrics of a single OPNsense target selected
+// by the "target" query parameter, like the /probe endpoint of the blackbox_exporter.
+type Handler struct {
+	log     *slog.Logger
+	targets map[string]*collector.Collector
+}
+
+// NewHandler builds an OPNsense client and a collector for every target in the targets config.
+func NewHandler(conf *options.TargetsConfig, version string, log *slog.Logger) (*Handler, error) {
+	targets := make(map[string]*collector.Collector, len(conf.Targets))
+
+	for name, target := range conf.Targets {
+		targetCollector, err := collector.NewFromTarget(target, version, log.With("target", name))
+		if err != nil {
+			for _, built := range targets {
+				built.Stop()
+			}
+			return nil, errors.Join(fmt.Errorf("failed to build collector for target %s", name), err)
+		}
+		targets[name] = targetCollector
+	}
+
+	return &Handler{
+		log:     log,
+		targets: targets,
+	}, nil
+}
+
+// Stop stops the background refresh of the collectors of all targets.
+func (h *Handler) Stop() {
+	for _, targetCollector := range h.targets {
+		targetCollector.Stop()
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+// Every request is served from a fresh registry that holds
+// only the collector of the requested target, bounded by the scrape timeout.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("target")
+	if name == "" {
+		http.Error(w, "target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	targetCollector, ok := h.targets[name]
+	if !ok {
+		h.log.Debug("probe for unknown target", "target", name)
+		http.Error(w, fmt.Sprintf("unknown target %q", name), http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel, err := collector.ScrapeContext(r, *options.TimeoutOffset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer cancel()
+
+	scrape, err := targetCollector.WithContext(ctx, r.URL.Query()["collect[]"]...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(scrape)
+
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
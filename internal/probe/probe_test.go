@@ -0,0 +1,69 @@
+package probe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/prometheus/common/promslog"
+)
+
+func TestHandler(t *testing.T) {
+	conf := &options.TargetsConfig{
+		Targets: map[string]options.TargetConfig{
+			"fw1": {
+				OPNSenseConfig: options.OPNSenseConfig{
+					Protocol:  "https",
+					Host:      "fw1.example.com",
+					APIKey:    "test",
+					APISecret: "test",
+				},
+				InstanceLabel: "fw1",
+			},
+		},
+	}
+
+	handler, err := NewHandler(conf, "test", promslog.NewNopLogger())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected int
+	}{
+		{
+			name:     "Missing target",
+			url:      "/probe",
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Unknown target",
+			url:      "/probe?target=fw2",
+			expected: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.url, nil))
+			if rec.Code != tc.expected {
+				t.Errorf("expected status %d, got %d", tc.expected, rec.Code)
+			}
+		})
+	}
+
+	disabled := false
+	conf.Targets["fw1"] = options.TargetConfig{
+		OPNSenseConfig: conf.Targets["fw1"].OPNSenseConfig,
+		Collectors: map[string]options.CollectorConfig{
+			"does_not_exist": {Enabled: &disabled},
+		},
+	}
+	if _, err := NewHandler(conf, "test", promslog.NewNopLogger()); err == nil {
+		t.Errorf("expected unknown collector error, got nil")
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,10 +9,8 @@ import (
 
 	"github.com/AthennaMind/opnsense-exporter/internal/collector"
 	"github.com/AthennaMind/opnsense-exporter/internal/options"
-	"github.com/AthennaMind/opnsense-exporter/opnsense"
 	"github.com/prometheus/client_golang/prometheus"
 	promcollectors "github.com/prometheus/client_golang/prometheus/collectors"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/promslog"
 	"github.com/prometheus/exporter-toolkit/web"
 )
@@ -21,31 +18,23 @@ import (
 var version = ""
 
 func main() {
-	options.Init()
+	options.RegisterCollectorFlags(collector.Defaults())
+	command := options.Init()
 	logger := promslog.New(options.PromLogConfig)
 
-	runtime.GOMAXPROCS(*options.MaxProcs)
-
-	logger.Info("starting opnsense-exporter", "version", version)
-	logger.Info("settings Go MAXPROCS", "procs", runtime.GOMAXPROCS(0))
-
-	opnsConfig, err := options.OPNSense()
-	if err != nil {
-		logger.Error("failed to assemble OPNsense configuration", "err", err)
+	if !options.SingleTargetConfigured() && *options.ProbeTargetsFile == "" {
+		logger.Error("either --opnsense.address, --config.file or --probe.targets-file must be set")
 		os.Exit(1)
 	}
 
-	opnsenseClient, err := opnsense.NewClient(
-		*opnsConfig,
-		version,
-		logger,
-	)
-	if err != nil {
-		logger.Error("opnsense client build failed", "err", err)
-		os.Exit(1)
+	if command == options.CheckCommand {
+		os.Exit(runCheck(logger))
 	}
 
-	logger.Debug(fmt.Sprintf("OPNsense registered endpoints %s", opnsenseClient.Endpoints()))
+	runtime.GOMAXPROCS(*options.MaxProcs)
+
+	logger.Info("starting opnsense-exporter", "version", version)
+	logger.Info("settings Go MAXPROCS", "procs", runtime.GOMAXPROCS(0))
 
 	registry := prometheus.NewRegistry()
 
@@ -56,51 +45,17 @@ func main() {
 		registry.MustRegister(promcollectors.NewGoCollector())
 	}
 
-	collectorsSwitches := options.CollectorsSwitches()
-	collectorOptionFuncs := []collector.Option{}
-
-	if !collectorsSwitches.Unbound {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutUnboundCollector())
-		logger.Info("unbound collector disabled")
-	}
-	if !collectorsSwitches.Wireguard {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutWireguardCollector())
-		logger.Info("wireguard collector disabled")
-	}
-	if !collectorsSwitches.IPsec {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutIPsecCollector())
-		logger.Info("ipesc collector disabled")
-	}
-	if !collectorsSwitches.Cron {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutCronCollector())
-		logger.Info("cron collector disabled")
-	}
-	if !collectorsSwitches.ARP {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutArpTableCollector())
-		logger.Info("arp collector disabled")
-	}
-	if !collectorsSwitches.Firewall {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutFirewallCollector())
-		logger.Info("firewall collector disabled")
-	}
-	if !collectorsSwitches.Firmware {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutFirmwareCollector())
-		logger.Info("firmware collector disabled")
-	}
-	if !collectorsSwitches.OpenVPN {
-		collectorOptionFuncs = append(collectorOptionFuncs, collector.WithoutOpenVPNCollector())
-		logger.Info("openvpn collector disabled")
-	}
-
-	collectorInstance, err := collector.New(&opnsenseClient, logger, *options.InstanceLabel, collectorOptionFuncs...)
-	if err != nil {
-		logger.Error("failed to construct the collecotr", "err", err)
+	exp := newExporter(registry, logger)
+	if err := exp.reload(); err != nil {
+		logger.Error("failed to load configuration", "err", err)
 		os.Exit(1)
 	}
 
-	registry.MustRegister(collectorInstance)
-	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	http.Handle(*options.MetricsPath, handler)
+	http.HandleFunc(*options.MetricsPath, exp.serveMetrics)
+	http.HandleFunc("/-/reload", exp.serveReload)
+	if *options.ProbeTargetsFile != "" {
+		http.HandleFunc("/probe", exp.serveProbe)
+	}
 
 	if *options.MetricsPath != "/" && *options.MetricsPath != "" {
 		landingConfig := web.LandingConfig{
@@ -114,6 +69,12 @@ func main() {
 				},
 			},
 		}
+		if *options.ProbeTargetsFile != "" {
+			landingConfig.Links = append(landingConfig.Links, web.LandingLinks{
+				Address: "/probe",
+				Text:    "Probe",
+			})
+		}
 		landingPage, err := web.NewLandingPage(landingConfig)
 		if err != nil {
 			logger.Error("failed to construct landing page", "err", err)
@@ -123,8 +84,10 @@ func main() {
 	}
 
 	term := make(chan os.Signal, 1)
+	hup := make(chan os.Signal, 1)
 	srvClose := make(chan struct{})
 	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(hup, syscall.SIGHUP)
 
 	srv := &http.Server{}
 	go func() {
@@ -139,6 +102,12 @@ func main() {
 		case <-term:
 			logger.Info("Received SIGTERM, exiting gracefully...")
 			os.Exit(0)
+		case <-hup:
+			if err := exp.reload(); err != nil {
+				logger.Error("failed to reload configuration; keeping the previous one", "err", err)
+				continue
+			}
+			logger.Info("configuration reloaded")
 		case <-srvClose:
 			os.Exit(1)
 		}
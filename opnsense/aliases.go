@@ -0,0 +1,92 @@
+package opnsense
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type aliasSearchResponse struct {
+	Rows []struct {
+		UUID         string `json:"uuid"`
+		Enabled      string `json:"enabled"`
+		Name         string `json:"name"`
+		Type         string `json:"type"`
+		Description  string `json:"description"`
+		CurrentItems string `json:"current_items"`
+		LastUpdated  string `json:"last_updated"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+// Alias holds the settings of a firewall alias and the state of its pf table.
+type Alias struct {
+	Name        string
+	Type        string
+	Description string
+	Enabled     bool
+	// Entries is the number of entries in the pf table of the alias, -1 if the table is not loaded.
+	Entries float64
+	// LastUpdated is the time of the last successful update of URL table and GeoIP aliases,
+	// the zero time if the alias was never updated or has no remote source.
+	LastUpdated time.Time
+}
+
+const fetchAliasesPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":""}`
+
+// FetchAliases fetches the aliases with the number of entries of their pf tables.
+// The internal aliases, e.g. bogons and sshlockout, are included.
+func (c *Client) FetchAliases(ctx context.Context) ([]Alias, *APICallError) {
+	var resp aliasSearchResponse
+	var data []Alias
+
+	path, ok := c.endpoints["aliases"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "aliases",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "POST", path, strings.NewReader(fetchAliasesPayload), &resp); err != nil {
+		return data, err
+	}
+
+	for _, row := range resp.Rows {
+		alias := Alias{
+			Name:        row.Name,
+			Type:        row.Type,
+			Description: row.Description,
+			Enabled:     row.Enabled == "1",
+			Entries:     -1,
+		}
+
+		// malformed values only leave out the value of the alias, not all aliases
+		if row.CurrentItems != "" {
+			entries, err := strconv.ParseFloat(row.CurrentItems, 64)
+			if err != nil {
+				c.log.Warn("failed to parse the number of entries of the alias",
+					"component", "opnsense-client", "alias", row.Name, "current_items", row.CurrentItems, "err", err)
+			} else {
+				alias.Entries = entries
+			}
+		}
+		if row.LastUpdated != "" {
+			lastUpdated, err := time.Parse(time.RFC3339, row.LastUpdated)
+			if err != nil {
+				c.log.Warn("failed to parse the last update of the alias",
+					"component", "opnsense-client", "alias", row.Name, "last_updated", row.LastUpdated, "err", err)
+			} else {
+				alias.LastUpdated = lastUpdated
+			}
+		}
+
+		data = append(data, alias)
+	}
+
+	return data, nil
+}
@@ -1,6 +1,7 @@
 package opnsense
 
 import (
+	"context"
 	"strings"
 )
 
@@ -40,7 +41,7 @@ type ArpTable struct {
 
 const fetchArpPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":"","resolve":"no"}`
 
-func (c *Client) FetchArpTable() (ArpTable, *APICallError) {
+func (c *Client) FetchArpTable(ctx context.Context) (ArpTable, *APICallError) {
 	var resp arpSearchResponse
 	var arpTable ArpTable
 
@@ -53,7 +54,7 @@ func (c *Client) FetchArpTable() (ArpTable, *APICallError) {
 		}
 	}
 
-	if err := c.do("POST", path, strings.NewReader(fetchArpPayload), &resp); err != nil {
+	if err := c.do(ctx, "POST", path, strings.NewReader(fetchArpPayload), &resp); err != nil {
 		return arpTable, err
 	}
 
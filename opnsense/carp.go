@@ -0,0 +1,102 @@
+package opnsense
+
+import (
+	"context"
+	"strings"
+)
+
+type carpStatusResponse struct {
+	Rows []struct {
+		Interface   string        `json:"interface"`
+		Vhid        string        `json:"vhid"`
+		Advbase     numericString `json:"advbase"`
+		Advskew     numericString `json:"advskew"`
+		Subnet      string        `json:"subnet"`
+		Mode        string        `json:"mode"`
+		Status      string        `json:"status"`
+		Description string        `json:"descr"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+	Carp     struct {
+		Demotion        numericString `json:"demotion"`
+		MaintenanceMode bool          `json:"maintenancemode"`
+	} `json:"carp"`
+}
+
+// CarpVIP holds the status of a CARP virtual IP.
+type CarpVIP struct {
+	Interface   string
+	Vhid        string
+	Address     string
+	Description string
+	// Status is the CARP state of the virtual IP in lower case: master, backup or init
+	Status string
+	// Advbase is the base advertisement interval in seconds
+	Advbase float64
+	Advskew float64
+}
+
+// CarpStatus holds the status of the CARP virtual IPs and the global CARP settings.
+type CarpStatus struct {
+	VIPs            []CarpVIP
+	Demotion        float64
+	MaintenanceMode bool
+}
+
+// FetchCarpStatus fetches the status of the CARP virtual IPs.
+// Virtual IPs of other modes, e.g. IP aliases, are skipped.
+func (c *Client) FetchCarpStatus(ctx context.Context) (CarpStatus, *APICallError) {
+	var resp carpStatusResponse
+	var data CarpStatus
+
+	path, ok := c.endpoints["carpStatus"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "carpStatus",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "GET", path, nil, &resp); err != nil {
+		return data, err
+	}
+
+	for _, row := range resp.Rows {
+		if row.Mode != "carp" {
+			continue
+		}
+
+		advbase, err := parseStringToFloat(string(row.Advbase), path)
+		if err != nil {
+			return data, err
+		}
+		advskew, err := parseStringToFloat(string(row.Advskew), path)
+		if err != nil {
+			return data, err
+		}
+
+		data.VIPs = append(data.VIPs, CarpVIP{
+			Interface:   row.Interface,
+			Vhid:        row.Vhid,
+			Address:     row.Subnet,
+			Description: row.Description,
+			Status:      strings.ToLower(row.Status),
+			Advbase:     advbase,
+			Advskew:     advskew,
+		})
+	}
+
+	if resp.Carp.Demotion != "" {
+		demotion, err := parseStringToFloat(string(resp.Carp.Demotion), path)
+		if err != nil {
+			return data, err
+		}
+		data.Demotion = demotion
+	}
+	data.MaintenanceMode = resp.Carp.MaintenanceMode
+
+	return data, nil
+}
@@ -0,0 +1,111 @@
+package opnsense
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// endpointPrivileges holds the OPNsense privilege that grants access
+// to each endpoint, as shown in System: Access: Users or Groups.
+var endpointPrivileges = map[EndpointName]string{
+	"services":                "Status: Services",
+	"interfaces":              "Reporting: Traffic",
+	"protocolStatistics":      "Diagnostics: Netstat",
+	"pfStatisticsByInterface": "Diagnostics: Firewall statistics",
+	"pfStatisticsInfo":        "Diagnostics: Firewall statistics",
+	"pfStatisticsMemory":      "Diagnostics: Firewall statistics",
+	"pfStatisticsTimeouts":    "Diagnostics: Firewall statistics",
+	"pfStatisticsRules":       "Diagnostics: Firewall statistics",
+	"firewallRules":           "Firewall: Automation: Filter",
+	"aliases":                 "Firewall: Aliases",
+	"firewallLog":             "Diagnostics: Firewall Log",
+	"interfacesOverview":      "Status: Interfaces",
+	"carpStatus":              "Status: CARP",
+	"routes":                  "Diagnostics: Routes",
+	"arp":                     "Diagnostics: ARP Table",
+	"ndp":                     "Diagnostics: NDP Table",
+	"dhcpv4":                  "Status: DHCP leases",
+	"keaLeases4":              "Services: Kea DHCP: Leases",
+	"keaSubnets4":             "Services: Kea DHCP: Settings",
+	"keaLeases6":              "Services: Kea DHCP: Leases",
+	"keaSubnets6":             "Services: Kea DHCP: Settings",
+	"dnsmasqLeases":           "Services: Dnsmasq DNS/DHCP: Leases",
+	"dnsmasqRanges":           "Services: Dnsmasq DNS/DHCP: Settings",
+	"openVPNInstances":        "VPN: OpenVPN: Instances",
+	"openVPNSessions":         "Status: OpenVPN",
+	"gatewaysStatus":          "System: Gateways",
+	"unboundDNSStatus":        "Status: DNS Overview",
+	"unboundDNSTotals":        "Status: DNS Overview",
+	"cronJobs":                "System: Settings: Cron",
+	"wireguardClients":        "VPN: WireGuard",
+	"ipsecPhase1":             "Status: IPsec",
+	"ipsecPhase2":             "Status: IPsec",
+	"healthCheck":             "System: Status",
+	"firmware":                "System: Firmware",
+	"firmwareInfo":            "System: Firmware",
+	"systemResources":         "Lobby: Dashboard",
+	"systemDisk":              "Lobby: Dashboard",
+	"systemSwap":              "Lobby: Dashboard",
+	"systemTime":              "Lobby: Dashboard",
+	"systemActivity":          "Diagnostics: System Activity",
+	"systemTemperature":       "Lobby: Dashboard",
+}
+
+// searchPayloads holds the payloads of the search endpoints, which are
+// requested with POST. The payloads are the same as in the requests of the collectors.
+var searchPayloads = map[EndpointName]string{
+	"firewallRules":    fetchFilterRulesPayload,
+	"aliases":          fetchAliasesPayload,
+	"arp":              fetchArpPayload,
+	"ndp":              fetchNdpPayload,
+	"dhcpv4":           fetchDHCPv4LeasesPayload,
+	"keaLeases4":       fetchKeaPayload,
+	"keaSubnets4":      fetchKeaPayload,
+	"keaLeases6":       fetchKeaPayload,
+	"keaSubnets6":      fetchKeaPayload,
+	"dnsmasqLeases":    fetchDnsmasqPayload,
+	"dnsmasqRanges":    fetchDnsmasqPayload,
+	"openVPNInstances": fetchOpenVPNPayload,
+	"cronJobs":         fetchCronPayload,
+	"ipsecPhase2":      `{"id":""}`,
+}
+
+// EndpointPrivilege returns the OPNsense privilege that grants
+// access to the endpoint, or an empty string if it is not known.
+func EndpointPrivilege(name EndpointName) string {
+	return endpointPrivileges[name]
+}
+
+// CheckEndpoint sends a single request to the endpoint, with the method and
+// payload of the collectors, and returns the HTTP status code and the latency of the request.
+// The status code is 0 if no response was received.
+func (c *Client) CheckEndpoint(ctx context.Context, name EndpointName) (int, time.Duration, *APICallError) {
+	var resp json.RawMessage
+
+	path, ok := c.endpoints[name]
+	if !ok {
+		return 0, 0, &APICallError{
+			Endpoint:   string(name),
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	method, body := "GET", io.Reader(nil)
+	if payload, ok := searchPayloads[name]; ok {
+		method, body = "POST", strings.NewReader(payload)
+	}
+
+	start := time.Now()
+	err := c.do(ctx, method, path, body, &resp)
+	latency := time.Since(start)
+	if err != nil {
+		return err.StatusCode, latency, err
+	}
+
+	return http.StatusOK, latency, nil
+}
@@ -0,0 +1,58 @@
+package opnsense
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+)
+
+func TestCheckEndpointRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		endpoint       EndpointName
+		expectedMethod string
+		expectedBody   string
+	}{
+		{name: "Status endpoint", endpoint: "services", expectedMethod: "GET"},
+		{name: "Search endpoint", endpoint: "arp", expectedMethod: "POST", expectedBody: fetchArpPayload},
+		{name: "Search endpoint with filter", endpoint: "dhcpv4", expectedMethod: "POST", expectedBody: fetchDHCPv4LeasesPayload},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var method, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				payload, _ := io.ReadAll(r.Body)
+				method, body = r.Method, string(payload)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(options.OPNSenseConfig{
+				Protocol:  "http",
+				Host:      strings.TrimPrefix(server.URL, "http://"),
+				APIKey:    "key",
+				APISecret: "secret",
+			}, "test", slog.New(slog.DiscardHandler))
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			if _, _, err := client.CheckEndpoint(context.Background(), tc.endpoint); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if method != tc.expectedMethod {
+				t.Errorf("expected method %s, got %s", tc.expectedMethod, method)
+			}
+			if body != tc.expectedBody {
+				t.Errorf("expected body %q, got %q", tc.expectedBody, body)
+			}
+		})
+	}
+}
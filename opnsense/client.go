@@ -1,26 +1,34 @@
 package opnsense
 
 import (
+	"bytes"
 	"compress/gzip"
-	"crypto/tls"
-	"crypto/x509"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	neturl "net/url"
 	"regexp"
-	"runtime"
 	"time"
 
 	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // MaxRetries is the maximum number of retries
 // when a request to the OPNsense API fails
 const MaxRetries = 3
 
+// defaultRequestTimeout bounds a request and its retries
+// when the context of the request has no deadline.
+const defaultRequestTimeout = 15 * time.Second
+
+// retryDelay is the delay between the retries of a failed request.
+const retryDelay = 25 * time.Millisecond
+
 // EndpointName is the custom type for name of an endpoint definition
 type EndpointName string
 
@@ -35,6 +43,7 @@ type Client struct {
 	log              *slog.Logger
 	headers          map[string]string
 	endpoints        map[EndpointName]EndpointPath
+	requestDuration  prometheus.ObserverVec
 	baseURL          string
 	key              string
 	secret           string
@@ -43,9 +52,9 @@ type Client struct {
 
 // NewClient creates a new OPNsense API Client
 func NewClient(cfg options.OPNSenseConfig, userAgentVersion string, log *slog.Logger) (Client, error) {
-	sslPool, err := x509.SystemCertPool()
+	transport, err := newTransport(cfg)
 	if err != nil {
-		return Client{}, errors.Join(fmt.Errorf("failed to load system cert pool"), err)
+		return Client{}, err
 	}
 
 	gatewayLossRegex, err := regexp.Compile(`\d\.\d %`)
@@ -69,18 +78,43 @@ func NewClient(cfg options.OPNSenseConfig, userAgentVersion string, log *slog.Lo
 			"interfaces":              "api/diagnostics/traffic/interface",
 			"protocolStatistics":      "api/diagnostics/interface/get_protocol_statistics",
 			"pfStatisticsByInterface": "api/diagnostics/firewall/pf_statistics/interfaces",
+			"pfStatisticsInfo":        "api/diagnostics/firewall/pf_statistics/info",
+			"pfStatisticsMemory":      "api/diagnostics/firewall/pf_statistics/memory",
+			"pfStatisticsTimeouts":    "api/diagnostics/firewall/pf_statistics/timeouts",
+			"pfStatisticsRules":       "api/diagnostics/firewall/pf_statistics/rules",
+			"firewallRules":           "api/firewall/filter/search_rule",
+			"aliases":                 "api/firewall/alias/search_item",
+			"firewallLog":             "api/diagnostics/firewall/log",
+			"interfacesOverview":      "api/interfaces/overview/interfacesInfo",
+			"carpStatus":              "api/diagnostics/interface/get_vip_status",
+			"routes":                  "api/diagnostics/interface/get_routes",
 			"arp":                     "api/diagnostics/interface/search_arp",
+			"ndp":                     "api/diagnostics/interface/search_ndp",
 			"dhcpv4":                  "api/dhcpv4/leases/searchLease",
+			"keaLeases4":              "api/kea/leases4/search",
+			"keaSubnets4":             "api/kea/dhcpv4/search_subnet",
+			"keaLeases6":              "api/kea/leases6/search",
+			"keaSubnets6":             "api/kea/dhcpv6/search_subnet",
+			"dnsmasqLeases":           "api/dnsmasq/leases/search",
+			"dnsmasqRanges":           "api/dnsmasq/settings/search_range",
 			"openVPNInstances":        "api/openvpn/instances/search",
 			"openVPNSessions":         "api/openvpn/service/search_sessions",
 			"gatewaysStatus":          "api/routing/settings/searchGateway",
 			"unboundDNSStatus":        "api/unbound/diagnostics/stats",
+			"unboundDNSTotals":        "api/unbound/overview/totals",
 			"cronJobs":                "api/cron/settings/searchJobs",
 			"wireguardClients":        "api/wireguard/service/show",
 			"ipsecPhase1":             "api/ipsec/sessions/search_phase1",
 			"ipsecPhase2":             "api/ipsec/sessions/search_phase2",
 			"healthCheck":             "api/core/system/status",
 			"firmware":                "api/core/firmware/status",
+			"firmwareInfo":            "api/core/firmware/info",
+			"systemResources":         "api/diagnostics/system/systemResources",
+			"systemDisk":              "api/diagnostics/system/systemDisk",
+			"systemSwap":              "api/diagnostics/system/systemSwap",
+			"systemTime":              "api/diagnostics/system/systemTime",
+			"systemActivity":          "api/diagnostics/activity/getActivity",
+			"systemTemperature":       "api/diagnostics/system/systemTemperature",
 		},
 		headers: map[string]string{
 			"Accept":          "application/json",
@@ -89,18 +123,7 @@ func NewClient(cfg options.OPNSenseConfig, userAgentVersion string, log *slog.Lo
 		},
 		sslInsecure: cfg.Insecure,
 		httpClient: &http.Client{
-			Timeout: 15 * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: cfg.Insecure,
-					RootCAs:            sslPool,
-				},
-				IdleConnTimeout:       90 * time.Second,
-				TLSHandshakeTimeout:   3 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-				ForceAttemptHTTP2:     true,
-				MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
-			},
+			Transport: transport,
 		},
 	}
 
@@ -113,41 +136,91 @@ func (c *Client) Endpoints() map[EndpointName]EndpointPath {
 	return c.endpoints
 }
 
+// SetRequestDurationObserver sets the observer that records
+// the duration of every request sent to the OPNsense API.
+// The observer must accept a single "endpoint" label.
+func (c *Client) SetRequestDurationObserver(observer prometheus.ObserverVec) {
+	c.requestDuration = observer
+}
+
 // do sends a request to the OPNsense API.
 // The response is unmarshalled
-// into the responseStruc
-func (c *Client) do(method string, path EndpointPath, body io.Reader, responseStruct any) *APICallError {
-	url := fmt.Sprintf("%s/%s", c.baseURL, string(path))
+// into the responseStruc.
+// The request and its retries are aborted when the context is done.
+func (c *Client) do(ctx context.Context, method string, path EndpointPath, body io.Reader, responseStruct any) *APICallError {
+	return c.doWithParams(ctx, method, path, nil, body, responseStruct)
+}
 
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return &APICallError{
-			Endpoint:   string(path),
-			Message:    err.Error(),
-			StatusCode: 0,
-		}
+// doWithParams sends a request to the OPNsense API like do,
+// with the params appended to the path as additional path segments.
+// Errors and request durations are still reported for the path without the params.
+func (c *Client) doWithParams(ctx context.Context, method string, path EndpointPath, params []string, body io.Reader, responseStruct any) *APICallError {
+	url := fmt.Sprintf("%s/%s", c.baseURL, string(path))
+	for _, param := range params {
+		url += "/" + neturl.PathEscape(param)
 	}
+	return c.send(ctx, method, path, url, body, responseStruct)
+}
 
-	req.SetBasicAuth(c.key, c.secret)
+// doWithQuery sends a request to the OPNsense API like do,
+// with the query appended to the path as query string.
+// Errors and request durations are still reported for the path without the query.
+func (c *Client) doWithQuery(ctx context.Context, method string, path EndpointPath, query neturl.Values, body io.Reader, responseStruct any) *APICallError {
+	url := fmt.Sprintf("%s/%s", c.baseURL, string(path))
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+	return c.send(ctx, method, path, url, body, responseStruct)
+}
 
-	for k, v := range c.headers {
-		req.Header.Add(k, v)
+// send sends the request to the url and decodes the JSON response into the responseStruct.
+// The request is bounded by the deadline of the context, or by defaultRequestTimeout without one.
+func (c *Client) send(ctx context.Context, method string, path EndpointPath, url string, body io.Reader, responseStruct any) *APICallError {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultRequestTimeout)
+		defer cancel()
 	}
 
-	if method == "POST" {
-		req.Header.Add("Content-Type", "application/json;charset=utf-8")
+	// the body is read once, as every retry needs a new request with the whole body
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return &APICallError{
+				Endpoint:   string(path),
+				Message:    fmt.Sprintf("failed to read request body: %s", err.Error()),
+				StatusCode: 0,
+			}
+		}
 	}
 
 	c.log.Debug("fetching data", "component", "opnsense-client", "url", url, "method", method)
 
 	// Retry the request up to MaxRetries times
 	for i := 0; i < MaxRetries; i++ {
+		req, apiErr := c.newRequest(ctx, method, path, url, payload)
+		if apiErr != nil {
+			return apiErr
+		}
+
+		start := time.Now()
 		resp, err := c.httpClient.Do(req)
+		if c.requestDuration != nil {
+			c.requestDuration.WithLabelValues(string(path)).Observe(time.Since(start).Seconds())
+		}
 		if err != nil {
+			if ctx.Err() != nil {
+				return abortedError(ctx, path)
+			}
 			c.log.Error("failed to send request; retrying",
 				"component", "opnsense-client",
 				"err", err.Error())
-			time.Sleep(25 * time.Millisecond)
+			select {
+			case <-ctx.Done():
+				return abortedError(ctx, path)
+			case <-time.After(retryDelay):
+			}
 			continue
 		}
 
@@ -205,3 +278,41 @@ func (c *Client) do(method string, path EndpointPath, body io.Reader, responseSt
 		StatusCode: 0,
 	}
 }
+
+// newRequest builds a request to the OPNsense API with the authentication and the headers of the client.
+func (c *Client) newRequest(ctx context.Context, method string, path EndpointPath, url string, payload []byte) (*http.Request, *APICallError) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, &APICallError{
+			Endpoint:   string(path),
+			Message:    err.Error(),
+			StatusCode: 0,
+		}
+	}
+
+	req.SetBasicAuth(c.key, c.secret)
+
+	for k, v := range c.headers {
+		req.Header.Add(k, v)
+	}
+
+	if method == "POST" {
+		req.Header.Add("Content-Type", "application/json;charset=utf-8")
+	}
+
+	return req, nil
+}
+
+// abortedError returns the error of a request that was aborted because the context is done.
+func abortedError(ctx context.Context, path EndpointPath) *APICallError {
+	return &APICallError{
+		Endpoint:   string(path),
+		Message:    fmt.Sprintf("request aborted: %s", context.Cause(ctx).Error()),
+		StatusCode: 0,
+	}
+}
@@ -0,0 +1,98 @@
+package opnsense
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/prometheus/common/promslog"
+)
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+
+	client, err := NewClient(options.OPNSenseConfig{
+		Protocol:  "http",
+		Host:      strings.TrimPrefix(server.URL, "http://"),
+		APIKey:    "key",
+		APISecret: "secret",
+	}, "test", promslog.NewNopLogger())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return &client
+}
+
+// closeConnection closes the connection of the request without a response,
+// so the client fails to send the request and retries it.
+func closeConnection(t *testing.T, w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Errorf("failed to hijack the connection: %v", err)
+		return
+	}
+	conn.Close()
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	var mutex sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mutex.Lock()
+		bodies = append(bodies, string(body))
+		attempt := len(bodies)
+		mutex.Unlock()
+
+		if attempt == 1 {
+			closeConnection(t, w)
+			return
+		}
+		w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	var resp struct {
+		Rows []any `json:"rows"`
+	}
+	payload := `{"current":1,"rowCount":-1}`
+	if err := client.do(context.Background(), "POST", "api/test/search", strings.NewReader(payload), &resp); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != payload {
+			t.Errorf("expected the body %q in attempt %d, got %q", payload, i+1, body)
+		}
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		closeConnection(t, w)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var resp any
+	err := client.do(ctx, "GET", "api/test/status", nil, &resp)
+	if err == nil || !strings.Contains(err.Message, "request aborted") {
+		t.Errorf("expected the request to be aborted, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package opnsense
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -46,7 +47,7 @@ type CronTable struct {
 
 const fetchCronPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":"","resolve":"no"}`
 
-func (c *Client) FetchCronTable() (CronTable, *APICallError) {
+func (c *Client) FetchCronTable(ctx context.Context) (CronTable, *APICallError) {
 	var resp cronSearchResponse
 	var cronTable CronTable
 
@@ -59,7 +60,7 @@ func (c *Client) FetchCronTable() (CronTable, *APICallError) {
 		}
 	}
 
-	if err := c.do("POST", path, strings.NewReader(fetchCronPayload), &resp); err != nil {
+	if err := c.do(ctx, "POST", path, strings.NewReader(fetchCronPayload), &resp); err != nil {
 		return cronTable, err
 	}
 
@@ -1 +1,110 @@
 package opnsense
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type dhcpv4LeasesResponse struct {
+	Rows []struct {
+		Address        string `json:"address"`
+		Starts         string `json:"starts"`
+		Ends           string `json:"ends"`
+		Binding        string `json:"binding"`
+		ClientHostname string `json:"client-hostname"`
+		Type           string `json:"type"`
+		Status         string `json:"status"`
+		Description    string `json:"descr"`
+		Mac            string `json:"mac"`
+		Hostname       string `json:"hostname"`
+		State          string `json:"state"`
+		Manufacturer   string `json:"man"`
+		Interface      string `json:"if"`
+		InterfaceDescr string `json:"if_descr"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+// DHCPLease is a single lease of a DHCP server.
+type DHCPLease struct {
+	Address  string
+	Mac      string
+	Hostname string
+	// Interface is the description of the interface the lease was handed out on,
+	// or the device name if the interface has no description.
+	Interface string
+	// State is the state of the lease as reported by the DHCP server, e.g. active or expired.
+	State string
+	// Static is true for leases of static mappings (reservations).
+	Static bool
+	// Ends is the end of the lease, or the zero time if the DHCP server does not report it.
+	Ends time.Time
+}
+
+// DHCPPool is an address range that a DHCP server hands out dynamically.
+type DHCPPool struct {
+	Interface string
+	// Range is the first and the last address of the pool, e.g. "192.168.1.100-192.168.1.199".
+	Range string
+}
+
+// DHCPLeases holds the leases and, if the DHCP server reports them, the pools of a DHCP server.
+type DHCPLeases struct {
+	Leases []DHCPLease
+	Pools  []DHCPPool
+}
+
+// dhcpv4LeaseTimeLayout is the layout of the start and end of the ISC DHCPv4 leases, in UTC.
+const dhcpv4LeaseTimeLayout = "2006/01/02 15:04:05"
+
+// fetchDHCPv4LeasesPayload requests all leases, including the inactive ones.
+const fetchDHCPv4LeasesPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":"","inactive":true}`
+
+// FetchDHCPv4Leases fetches the leases of the ISC DHCPv4 server.
+// The ISC DHCPv4 API does not report the pools.
+func (c *Client) FetchDHCPv4Leases(ctx context.Context) (DHCPLeases, *APICallError) {
+	var resp dhcpv4LeasesResponse
+	var data DHCPLeases
+
+	path, ok := c.endpoints["dhcpv4"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "dhcpv4",
+			Message:    "endpoint not found",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "POST", path, strings.NewReader(fetchDHCPv4LeasesPayload), &resp); err != nil {
+		return data, err
+	}
+
+	for _, lease := range resp.Rows {
+		iface := lease.InterfaceDescr
+		if iface == "" {
+			iface = lease.Interface
+		}
+		hostname := lease.Hostname
+		if hostname == "" {
+			hostname = lease.ClientHostname
+		}
+
+		// the end is only used to pick the newest of duplicate leases, so invalid values are ignored
+		ends, _ := time.Parse(dhcpv4LeaseTimeLayout, lease.Ends)
+
+		data.Leases = append(data.Leases, DHCPLease{
+			Address:   lease.Address,
+			Mac:       lease.Mac,
+			Hostname:  hostname,
+			Interface: iface,
+			State:     lease.State,
+			Static:    lease.Type == "static",
+			Ends:      ends,
+		})
+	}
+
+	return data, nil
+}
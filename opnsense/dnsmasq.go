@@ -0,0 +1,128 @@
+package opnsense
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+)
+
+type dnsmasqLeasesResponse struct {
+	Rows []struct {
+		Address       string `json:"address"`
+		HWAddr        string `json:"hwaddr"`
+		Hostname      string `json:"hostname"`
+		ClientID      string `json:"client_id"`
+		IsReserved    string `json:"is_reserved"`
+		Interface     string `json:"if"`
+		InterfaceName string `json:"if_descr"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+type dnsmasqRangesResponse struct {
+	Rows []struct {
+		UUID              string `json:"uuid"`
+		Interface         string `json:"interface"`
+		InterfaceName     string `json:"%interface"`
+		StartAddr         string `json:"start_addr"`
+		EndAddr           string `json:"end_addr"`
+		ConstructorPrefix string `json:"constructor"`
+		Description       string `json:"description"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+// DnsmasqLeases holds the DHCPv4 and the DHCPv6 leases and pools of Dnsmasq.
+type DnsmasqLeases struct {
+	V4 DHCPLeases
+	V6 DHCPLeases
+}
+
+const fetchDnsmasqPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":""}`
+
+// FetchDnsmasqLeases fetches the leases and the DHCP ranges of Dnsmasq,
+// split by address family. Dnsmasq only reports the current leases,
+// so all leases are active.
+func (c *Client) FetchDnsmasqLeases(ctx context.Context) (DnsmasqLeases, *APICallError) {
+	var leasesResp dnsmasqLeasesResponse
+	var rangesResp dnsmasqRangesResponse
+	var data DnsmasqLeases
+
+	leasesPath, ok := c.endpoints["dnsmasqLeases"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "dnsmasqLeases",
+			Message:    "endpoint not found",
+			StatusCode: 0,
+		}
+	}
+	rangesPath, ok := c.endpoints["dnsmasqRanges"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "dnsmasqRanges",
+			Message:    "endpoint not found",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "POST", leasesPath, strings.NewReader(fetchDnsmasqPayload), &leasesResp); err != nil {
+		return data, err
+	}
+	if err := c.do(ctx, "POST", rangesPath, strings.NewReader(fetchDnsmasqPayload), &rangesResp); err != nil {
+		return data, err
+	}
+
+	for _, lease := range leasesResp.Rows {
+		iface := lease.InterfaceName
+		if iface == "" {
+			iface = lease.Interface
+		}
+
+		dhcpLease := DHCPLease{
+			Address:   lease.Address,
+			Mac:       lease.HWAddr,
+			Hostname:  lease.Hostname,
+			Interface: iface,
+			State:     "active",
+			Static:    lease.IsReserved != "",
+		}
+		if isIPv6(lease.Address) {
+			data.V6.Leases = append(data.V6.Leases, dhcpLease)
+		} else {
+			data.V4.Leases = append(data.V4.Leases, dhcpLease)
+		}
+	}
+
+	for _, dhcpRange := range rangesResp.Rows {
+		// ranges of constructed IPv6 prefixes have no fixed addresses
+		if dhcpRange.StartAddr == "" || dhcpRange.EndAddr == "" || dhcpRange.ConstructorPrefix != "" {
+			continue
+		}
+		iface := dhcpRange.InterfaceName
+		if iface == "" {
+			iface = dhcpRange.Interface
+		}
+
+		pool := DHCPPool{
+			Interface: iface,
+			Range:     dhcpRange.StartAddr + "-" + dhcpRange.EndAddr,
+		}
+		if isIPv6(dhcpRange.StartAddr) {
+			data.V6.Pools = append(data.V6.Pools, pool)
+		} else {
+			data.V4.Pools = append(data.V4.Pools, pool)
+		}
+	}
+
+	return data, nil
+}
+
+// isIPv6 reports whether the address is an IPv6 address.
+func isIPv6(address string) bool {
+	addr, err := netip.ParseAddr(address)
+	return err == nil && addr.Is6() && !addr.Is4In6()
+}
@@ -1,5 +1,7 @@
 package opnsense
 
+import "context"
+
 type FirewallPFStat struct {
 	InterfaceName string `json:"interface,omitempty"` // We will populate this field with the key of the map
 	References    int    `json:"references"`
@@ -26,7 +28,7 @@ type FirewallPFStats struct {
 	Interfaces []FirewallPFStat
 }
 
-func (c *Client) FetchPFStatsByInterface() (FirewallPFStats, *APICallError) {
+func (c *Client) FetchPFStatsByInterface(ctx context.Context) (FirewallPFStats, *APICallError) {
 	var resp firewallPFStatsResponse
 	var data FirewallPFStats
 
@@ -39,7 +41,7 @@ func (c *Client) FetchPFStatsByInterface() (FirewallPFStats, *APICallError) {
 		}
 	}
 
-	err := c.do("GET", url, nil, &resp)
+	err := c.do(ctx, "GET", url, nil, &resp)
 	if err != nil {
 		return data, err
 	}
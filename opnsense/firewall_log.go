@@ -0,0 +1,72 @@
+package opnsense
+
+import (
+	"context"
+	neturl "net/url"
+	"strconv"
+)
+
+type firewallLogResponse []struct {
+	Digest    string `json:"__digest__"`
+	Interface string `json:"interface"`
+	Action    string `json:"action"`
+	Direction string `json:"dir"`
+	Protocol  string `json:"protoname"`
+	Label     string `json:"label"`
+	Source    string `json:"src"`
+	DestPort  string `json:"dstport"`
+}
+
+// FirewallLogEntry holds a single entry of the firewall live log.
+type FirewallLogEntry struct {
+	// Digest is the unique hash of the entry
+	Digest    string
+	Interface string
+	Action    string
+	Direction string
+	Protocol  string
+	// Label is the label of the pf rule that matched, the UUID for the rules of the firewall automation
+	Label    string
+	Source   string
+	DestPort string
+}
+
+// FetchFirewallLog fetches at most limit entries of the firewall live log,
+// newest first. If digest is not empty, only the entries that are newer than
+// the entry with the digest are returned.
+func (c *Client) FetchFirewallLog(ctx context.Context, digest string, limit int) ([]FirewallLogEntry, *APICallError) {
+	var resp firewallLogResponse
+	var data []FirewallLogEntry
+
+	path, ok := c.endpoints["firewallLog"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "firewallLog",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	query := neturl.Values{"limit": []string{strconv.Itoa(limit)}}
+	if digest != "" {
+		query.Set("digest", digest)
+	}
+	if err := c.doWithQuery(ctx, "GET", path, query, nil, &resp); err != nil {
+		return data, err
+	}
+
+	for _, entry := range resp {
+		data = append(data, FirewallLogEntry{
+			Digest:    entry.Digest,
+			Interface: entry.Interface,
+			Action:    entry.Action,
+			Direction: entry.Direction,
+			Protocol:  entry.Protocol,
+			Label:     entry.Label,
+			Source:    entry.Source,
+			DestPort:  entry.DestPort,
+		})
+	}
+
+	return data, nil
+}
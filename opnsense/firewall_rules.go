@@ -0,0 +1,117 @@
+package opnsense
+
+import (
+	"context"
+	"strings"
+)
+
+type pfRulesResponse struct {
+	Rules map[string]struct {
+		Evaluations    numericString `json:"evaluations"`
+		Packets        numericString `json:"packets"`
+		Bytes          numericString `json:"bytes"`
+		States         numericString `json:"states"`
+		StateCreations numericString `json:"state_creations"`
+	} `json:"rules"`
+}
+
+type filterRulesResponse struct {
+	Rows []struct {
+		UUID        string `json:"uuid"`
+		Enabled     string `json:"enabled"`
+		Sequence    string `json:"sequence"`
+		Action      string `json:"action"`
+		Interface   string `json:"interface"`
+		Direction   string `json:"direction"`
+		Description string `json:"description"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+// FirewallRuleStats holds the counters of a firewall rule.
+type FirewallRuleStats struct {
+	UUID        string
+	Description string
+	Interface   string
+	Action      string
+	Evaluations float64
+	Packets     float64
+	Bytes       float64
+	// States is the current number of states created by the rule
+	States         float64
+	StateCreations float64
+}
+
+const fetchFilterRulesPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":""}`
+
+// FetchFirewallRuleStats fetches the counters of the pf rules and joins them
+// with the firewall rules by the rule label, which is the UUID of the rule.
+// pf rules without a matching firewall rule, e.g. rules of the legacy GUI
+// or automatically generated rules, are returned with their label as UUID
+// and without description, interface and action. Disabled rules have no
+// pf rule and are not returned.
+func (c *Client) FetchFirewallRuleStats(ctx context.Context) ([]FirewallRuleStats, *APICallError) {
+	var statsResp pfRulesResponse
+	var rulesResp filterRulesResponse
+	var data []FirewallRuleStats
+
+	statsPath, ok := c.endpoints["pfStatisticsRules"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "pfStatisticsRules",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+	rulesPath, ok := c.endpoints["firewallRules"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "firewallRules",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "GET", statsPath, nil, &statsResp); err != nil {
+		return data, err
+	}
+	if err := c.do(ctx, "POST", rulesPath, strings.NewReader(fetchFilterRulesPayload), &rulesResp); err != nil {
+		return data, err
+	}
+
+	rules := make(map[string]int, len(rulesResp.Rows))
+	for i, rule := range rulesResp.Rows {
+		rules[rule.UUID] = i
+	}
+
+	for label, stats := range statsResp.Rules {
+		rule := FirewallRuleStats{UUID: label}
+		if i, ok := rules[label]; ok {
+			rule.Description = rulesResp.Rows[i].Description
+			rule.Interface = rulesResp.Rows[i].Interface
+			rule.Action = rulesResp.Rows[i].Action
+		}
+
+		var err *APICallError
+		values := make([]float64, 5)
+		for j, value := range []numericString{stats.Evaluations, stats.Packets, stats.Bytes, stats.States, stats.StateCreations} {
+			if value == "" {
+				continue
+			}
+			if values[j], err = parseStringToFloat(string(value), statsPath); err != nil {
+				return data, err
+			}
+		}
+
+		rule.Evaluations = values[0]
+		rule.Packets = values[1]
+		rule.Bytes = values[2]
+		rule.States = values[3]
+		rule.StateCreations = values[4]
+		data = append(data, rule)
+	}
+
+	return data, nil
+}
@@ -1,5 +1,7 @@
 package opnsense
 
+import "context"
+
 type firmwareStatusResponse struct {
 	LastCheck      string `json:"last_check"`
 	NeedsReboot    string `json:"needs_reboot"`
@@ -63,7 +65,7 @@ func NewFirmwareStatus() FirmwareStatus {
 	}
 }
 
-func (c *Client) FetchFirmwareStatus() (FirmwareStatus, *APICallError) {
+func (c *Client) FetchFirmwareStatus(ctx context.Context) (FirmwareStatus, *APICallError) {
 	var resp firmwareStatusResponse
 	data := NewFirmwareStatus()
 
@@ -77,7 +79,7 @@ func (c *Client) FetchFirmwareStatus() (FirmwareStatus, *APICallError) {
 		}
 	}
 
-	if err := c.do("GET", url, nil, &resp); err != nil {
+	if err := c.do(ctx, "GET", url, nil, &resp); err != nil {
 		return data, err
 	}
 
@@ -94,3 +96,63 @@ func (c *Client) FetchFirmwareStatus() (FirmwareStatus, *APICallError) {
 	}
 	return data, nil
 }
+
+type firmwareInfoResponse struct {
+	Product struct {
+		ProductVersion string `json:"product_version"`
+	} `json:"product"`
+	Package []firmwarePackage `json:"package"`
+	Plugin  []firmwarePackage `json:"plugin"`
+}
+
+type firmwarePackage struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Installed string `json:"installed"`
+}
+
+// FirmwareInfo is the inventory of the installed packages and plugins.
+type FirmwareInfo struct {
+	ProductVersion string
+	// Plugins holds the installed plugins by name and their version
+	Plugins map[string]string
+	// Packages holds the installed packages by name and their version
+	Packages map[string]string
+}
+
+// FetchFirmwareInfo returns the product version and the installed plugins and packages.
+func (c *Client) FetchFirmwareInfo(ctx context.Context) (FirmwareInfo, *APICallError) {
+	var resp firmwareInfoResponse
+	data := FirmwareInfo{
+		Plugins:  make(map[string]string),
+		Packages: make(map[string]string),
+	}
+
+	url, ok := c.endpoints["firmwareInfo"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "firmwareInfo",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "GET", url, nil, &resp); err != nil {
+		return data, err
+	}
+
+	data.ProductVersion = resp.Product.ProductVersion
+
+	for _, plugin := range resp.Plugin {
+		if plugin.Installed == "1" {
+			data.Plugins[plugin.Name] = plugin.Version
+		}
+	}
+	for _, pkg := range resp.Package {
+		if pkg.Installed == "1" {
+			data.Packages[pkg.Name] = pkg.Version
+		}
+	}
+
+	return data, nil
+}
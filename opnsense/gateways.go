@@ -1,6 +1,7 @@
 package opnsense
 
 import (
+	"context"
 	"log/slog"
 	"strconv"
 )
@@ -138,7 +139,7 @@ func parseGatewayStatus(statusTranslated string, logger *slog.Logger, originalSt
 
 // FetchGateways fetches the gateways status details from the OPNsense API
 // and returns a safe wrapper Gateways struct.
-func (c *Client) FetchGateways() (Gateways, *APICallError) {
+func (c *Client) FetchGateways(ctx context.Context) (Gateways, *APICallError) {
 	var resp gatewayConfigurationResponse
 	var data Gateways
 
@@ -150,7 +151,7 @@ func (c *Client) FetchGateways() (Gateways, *APICallError) {
 			StatusCode: 0,
 		}
 	}
-	err := c.do("GET", url, nil, &resp)
+	err := c.do(ctx, "GET", url, nil, &resp)
 	if err != nil {
 		return data, err
 	}
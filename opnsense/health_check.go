@@ -1,6 +1,7 @@
 package opnsense
 
 import (
+	"context"
 	"strconv"
 )
 
@@ -75,7 +76,7 @@ const (
 )
 
 // HealthCheck checks if the OPNsense is up and running.
-func (c *Client) HealthCheck() (HealthCheckResponse, error) {
+func (c *Client) HealthCheck(ctx context.Context) (HealthCheckResponse, error) {
 	var resp HealthCheckResponse
 
 	path, ok := c.endpoints["healthCheck"]
@@ -88,7 +89,7 @@ func (c *Client) HealthCheck() (HealthCheckResponse, error) {
 		}
 	}
 
-	if err := c.do("GET", path, nil, &resp); err != nil {
+	if err := c.do(ctx, "GET", path, nil, &resp); err != nil {
 		return HealthCheckResponse{}, err
 	}
 
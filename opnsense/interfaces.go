@@ -1,5 +1,11 @@
 package opnsense
 
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
 type InterfaceDetails struct {
 	Device                    string `json:"device"`
 	Driver                    string `json:"driver"`
@@ -55,13 +61,27 @@ type Interface struct {
 	InputErrors           int
 	OutputErrors          int
 	Collisions            int
+	// The following values are -1 when OPNsense returned a malformed value.
+	// LinkState is the link state of the interface, 0 = unknown, 1 = down, 2 = up
+	LinkState int
+	// LineRate is the line rate of the interface in bits per second
+	LineRate                  int
+	SendQueueLength           int
+	SendQueueMaxLength        int
+	SendQueueDrops            int
+	InputQueueDrops           int
+	PacketsForUnknownProtocol int
+	// StatsResetSystemUptime is the uptime of the system in seconds when the
+	// interface was attached or its counters were reset. OPNsense does not
+	// return the time of the reset itself.
+	StatsResetSystemUptime int
 }
 
 type Interfaces struct {
 	Interfaces []Interface
 }
 
-func (c *Client) FetchInterfaces() (Interfaces, *APICallError) {
+func (c *Client) FetchInterfaces(ctx context.Context) (Interfaces, *APICallError) {
 	var resp interfaceResponse
 	var data Interfaces
 
@@ -74,13 +94,12 @@ func (c *Client) FetchInterfaces() (Interfaces, *APICallError) {
 		}
 	}
 
-	err := c.do("GET", url, nil, &resp)
+	err := c.do(ctx, "GET", url, nil, &resp)
 	if err != nil {
 		return data, err
 	}
 
 	for _, v := range resp.Interface {
-
 		convertedValues, err := sliceIntToMapStringInt(
 			[]string{
 				v.MTU, v.BytesReceived, v.BytesTransmitted,
@@ -96,21 +115,42 @@ func (c *Client) FetchInterfaces() (Interfaces, *APICallError) {
 		}
 
 		data.Interfaces = append(data.Interfaces, Interface{
-			Name:                  v.Name,
-			Device:                v.Device,
-			Type:                  v.Type,
-			MTU:                   convertedValues[v.MTU],
-			BytesReceived:         convertedValues[v.BytesReceived],
-			BytesTransmitted:      convertedValues[v.BytesTransmitted],
-			PacketsReceived:       convertedValues[v.PacketsReceived],
-			PacketsTransmitted:    convertedValues[v.PacketsTransmitted],
-			MulticastsReceived:    convertedValues[v.MulticastsReceived],
-			MulticastsTransmitted: convertedValues[v.MulticastsTransmitted],
-			InputErrors:           convertedValues[v.InputErrors],
-			OutputErrors:          convertedValues[v.OutputErrors],
-			Collisions:            convertedValues[v.Collisions],
+			Name:                      v.Name,
+			Device:                    v.Device,
+			Type:                      v.Type,
+			MTU:                       convertedValues[v.MTU],
+			BytesReceived:             convertedValues[v.BytesReceived],
+			BytesTransmitted:          convertedValues[v.BytesTransmitted],
+			PacketsReceived:           convertedValues[v.PacketsReceived],
+			PacketsTransmitted:        convertedValues[v.PacketsTransmitted],
+			MulticastsReceived:        convertedValues[v.MulticastsReceived],
+			MulticastsTransmitted:     convertedValues[v.MulticastsTransmitted],
+			InputErrors:               convertedValues[v.InputErrors],
+			OutputErrors:              convertedValues[v.OutputErrors],
+			Collisions:                convertedValues[v.Collisions],
+			LinkState:                 c.parseInterfaceValue(v.Name, "link state", v.LinkState),
+			LineRate:                  c.parseInterfaceValue(v.Name, "line rate", strings.TrimSuffix(v.LineRate, " bit/s")),
+			SendQueueLength:           c.parseInterfaceValue(v.Name, "send queue length", v.SendQueueLength),
+			SendQueueMaxLength:        c.parseInterfaceValue(v.Name, "send queue max length", v.SendQueueMaxLength),
+			SendQueueDrops:            c.parseInterfaceValue(v.Name, "send queue drops", v.SendQueueDrops),
+			InputQueueDrops:           c.parseInterfaceValue(v.Name, "input queue drops", v.InputQueueDrops),
+			PacketsForUnknownProtocol: c.parseInterfaceValue(v.Name, "packets for unknown protocol", v.PacketsForUnknownProtocol),
+			StatsResetSystemUptime:    c.parseInterfaceValue(v.Name, "uptime at attach or stat reset", v.UptimeAtAttachOrStatReset),
 		})
 	}
 
 	return data, nil
 }
+
+// parseInterfaceValue parses an optional value of an interface.
+// Malformed values are logged and returned as -1, so they only
+// leave out the value of the interface, not all interfaces.
+func (c *Client) parseInterfaceValue(iface, field, value string) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		c.log.Warn("failed to parse the value of the interface",
+			"component", "opnsense-client", "interface", iface, "field", field, "value", value)
+		return -1
+	}
+	return parsed
+}
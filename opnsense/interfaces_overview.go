@@ -0,0 +1,95 @@
+package opnsense
+
+import "context"
+
+type interfaceAddress struct {
+	Address    string        `json:"ipaddr"`
+	SubnetBits numericString `json:"subnetbits"`
+}
+
+type interfacesOverviewResponse struct {
+	Rows []struct {
+		Identifier  string             `json:"identifier"`
+		Description string             `json:"description"`
+		Device      string             `json:"device"`
+		Enabled     bool               `json:"enabled"`
+		VlanTag     numericString      `json:"vlan_tag"`
+		IPv4        []interfaceAddress `json:"ipv4"`
+		IPv6        []interfaceAddress `json:"ipv6"`
+		Vlan        struct {
+			Tag    numericString `json:"tag"`
+			Parent string        `json:"parent"`
+		} `json:"vlan"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+// InterfaceAddress holds an address of an interface with its prefix length.
+type InterfaceAddress struct {
+	Address      string
+	PrefixLength string
+}
+
+// InterfaceOverview holds the configuration of a logical (assigned) interface.
+type InterfaceOverview struct {
+	// Identifier is the OPNsense identifier of the interface, e.g. wan, lan or opt1
+	Identifier  string
+	Description string
+	Device      string
+	Enabled     bool
+	// VlanTag and VlanParent are empty if the device is not a VLAN
+	VlanTag    string
+	VlanParent string
+	IPv4       []InterfaceAddress
+	IPv6       []InterfaceAddress
+}
+
+// FetchInterfacesOverview fetches the configuration of the logical interfaces.
+// Devices that are not assigned to an interface are skipped.
+func (c *Client) FetchInterfacesOverview(ctx context.Context) ([]InterfaceOverview, *APICallError) {
+	var resp interfacesOverviewResponse
+	var data []InterfaceOverview
+
+	path, ok := c.endpoints["interfacesOverview"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "interfacesOverview",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "GET", path, nil, &resp); err != nil {
+		return data, err
+	}
+
+	for _, row := range resp.Rows {
+		if row.Identifier == "" {
+			continue
+		}
+
+		iface := InterfaceOverview{
+			Identifier:  row.Identifier,
+			Description: row.Description,
+			Device:      row.Device,
+			Enabled:     row.Enabled,
+			VlanTag:     string(row.VlanTag),
+			VlanParent:  row.Vlan.Parent,
+		}
+		if iface.VlanTag == "" {
+			iface.VlanTag = string(row.Vlan.Tag)
+		}
+		for _, address := range row.IPv4 {
+			iface.IPv4 = append(iface.IPv4, InterfaceAddress{Address: address.Address, PrefixLength: string(address.SubnetBits)})
+		}
+		for _, address := range row.IPv6 {
+			iface.IPv6 = append(iface.IPv6, InterfaceAddress{Address: address.Address, PrefixLength: string(address.SubnetBits)})
+		}
+
+		data = append(data, iface)
+	}
+
+	return data, nil
+}
@@ -1,6 +1,7 @@
 package opnsense
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 	"strings"
@@ -70,7 +71,7 @@ type IPsecPhase1 struct {
 	Rows []IPsec
 }
 
-func (c *Client) FetchIPsecPhase2(ikeId string) (ipsecPhase2SearchResponse, *APICallError) {
+func (c *Client) FetchIPsecPhase2(ctx context.Context, ikeId string) (ipsecPhase2SearchResponse, *APICallError) {
 	var resp ipsecPhase2SearchResponse
 
 	url, ok := c.endpoints["ipsecPhase2"]
@@ -93,14 +94,14 @@ func (c *Client) FetchIPsecPhase2(ikeId string) (ipsecPhase2SearchResponse, *API
 		}
 	}
 
-	if err := c.do("POST", url, strings.NewReader(string(bodyBytes)), &resp); err != nil {
+	if err := c.do(ctx, "POST", url, strings.NewReader(string(bodyBytes)), &resp); err != nil {
 		return resp, err
 	}
 
 	return resp, nil
 }
 
-func (c *Client) FetchIPsecPhase1() (IPsecPhase1, *APICallError) {
+func (c *Client) FetchIPsecPhase1(ctx context.Context) (IPsecPhase1, *APICallError) {
 	var resp ipsecSearchResponse
 	var data IPsecPhase1
 
@@ -113,7 +114,7 @@ func (c *Client) FetchIPsecPhase1() (IPsecPhase1, *APICallError) {
 		}
 	}
 
-	if err := c.do("GET", url, nil, &resp); err != nil {
+	if err := c.do(ctx, "GET", url, nil, &resp); err != nil {
 		return data, err
 	}
 
@@ -125,7 +126,7 @@ func (c *Client) FetchIPsecPhase1() (IPsecPhase1, *APICallError) {
 		}
 
 		phase2Rows := []ipsecPhase2{}
-		phase2, err2 := c.FetchIPsecPhase2(v.IkeId)
+		phase2, err2 := c.FetchIPsecPhase2(ctx, v.IkeId)
 		if err2 != nil {
 			c.log.Error("failed to fetch ipsec phase2", "error", err2)
 		} else {
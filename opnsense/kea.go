@@ -0,0 +1,141 @@
+package opnsense
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+)
+
+type keaLeasesResponse struct {
+	Rows []struct {
+		Address       string `json:"address"`
+		HWAddr        string `json:"hwaddr"`
+		Hostname      string `json:"hostname"`
+		SubnetID      string `json:"subnet_id"`
+		State         string `json:"state"`
+		IsReserved    string `json:"is_reserved"`
+		Interface     string `json:"if"`
+		InterfaceName string `json:"if_descr"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+type keaSubnetsResponse struct {
+	Rows []struct {
+		UUID        string `json:"uuid"`
+		Subnet      string `json:"subnet"`
+		Pools       string `json:"pools"`
+		Description string `json:"description"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+// keaLeaseStates maps the numeric lease states of Kea to their names.
+var keaLeaseStates = map[string]string{
+	"0": "active",
+	"1": "declined",
+	"2": "expired",
+}
+
+const fetchKeaPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":""}`
+
+// FetchKeaLeases4 fetches the leases and the pools of the Kea DHCPv4 server.
+func (c *Client) FetchKeaLeases4(ctx context.Context) (DHCPLeases, *APICallError) {
+	return c.fetchKeaLeases(ctx, "keaLeases4", "keaSubnets4")
+}
+
+// FetchKeaLeases6 fetches the leases and the pools of the Kea DHCPv6 server.
+func (c *Client) FetchKeaLeases6(ctx context.Context) (DHCPLeases, *APICallError) {
+	return c.fetchKeaLeases(ctx, "keaLeases6", "keaSubnets6")
+}
+
+func (c *Client) fetchKeaLeases(ctx context.Context, leasesEndpoint, subnetsEndpoint EndpointName) (DHCPLeases, *APICallError) {
+	var leasesResp keaLeasesResponse
+	var subnetsResp keaSubnetsResponse
+	var data DHCPLeases
+
+	leasesPath, ok := c.endpoints[leasesEndpoint]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   string(leasesEndpoint),
+			Message:    "endpoint not found",
+			StatusCode: 0,
+		}
+	}
+	subnetsPath, ok := c.endpoints[subnetsEndpoint]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   string(subnetsEndpoint),
+			Message:    "endpoint not found",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "POST", leasesPath, strings.NewReader(fetchKeaPayload), &leasesResp); err != nil {
+		return data, err
+	}
+	if err := c.do(ctx, "POST", subnetsPath, strings.NewReader(fetchKeaPayload), &subnetsResp); err != nil {
+		return data, err
+	}
+
+	for _, lease := range leasesResp.Rows {
+		iface := lease.InterfaceName
+		if iface == "" {
+			iface = lease.Interface
+		}
+		state, ok := keaLeaseStates[lease.State]
+		if !ok {
+			state = lease.State
+		}
+
+		data.Leases = append(data.Leases, DHCPLease{
+			Address:   lease.Address,
+			Mac:       lease.HWAddr,
+			Hostname:  lease.Hostname,
+			Interface: iface,
+			State:     state,
+			Static:    lease.IsReserved != "",
+		})
+	}
+
+	for _, subnet := range subnetsResp.Rows {
+		iface := subnetInterface(subnet.Subnet, data.Leases)
+		if iface == "" {
+			iface = subnet.Description
+		}
+		for _, pool := range strings.FieldsFunc(subnet.Pools, func(r rune) bool {
+			return r == '\n' || r == ','
+		}) {
+			data.Pools = append(data.Pools, DHCPPool{
+				Interface: iface,
+				Range:     strings.TrimSpace(pool),
+			})
+		}
+	}
+
+	return data, nil
+}
+
+// subnetInterface returns the interface of the first lease within the subnet.
+// Kea subnets are not bound to an interface in OPNsense, so the interface is
+// only known from the leases. An empty string is returned if no lease is within the subnet.
+func subnetInterface(subnet string, leases []DHCPLease) string {
+	prefix, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return ""
+	}
+	for _, lease := range leases {
+		addr, err := netip.ParseAddr(lease.Address)
+		if err != nil {
+			continue
+		}
+		if lease.Interface != "" && prefix.Contains(addr) {
+			return lease.Interface
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package opnsense
+
+import (
+	"context"
+	"strings"
+)
+
+type ndpSearchResponse struct {
+	Rows []struct {
+		Mac             string `json:"mac"`
+		IP              string `json:"ip"`
+		Intf            string `json:"intf"`
+		Manufacturer    string `json:"manufacturer"`
+		IntfDescription string `json:"intf_description"`
+	} `json:"rows"`
+	Total    int `json:"total"`
+	RowCount int `json:"rowCount"`
+	Current  int `json:"current"`
+}
+
+// Ndp holds an entry of the IPv6 neighbor discovery (NDP) table.
+type Ndp struct {
+	Mac             string
+	IP              string
+	Intf            string
+	Manufacturer    string
+	IntfDescription string
+}
+
+type NdpTable struct {
+	Ndp          []Ndp
+	TotalEntries int
+}
+
+const fetchNdpPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":""}`
+
+// FetchNdpTable fetches the IPv6 neighbor discovery (NDP) table.
+func (c *Client) FetchNdpTable(ctx context.Context) (NdpTable, *APICallError) {
+	var resp ndpSearchResponse
+	var ndpTable NdpTable
+
+	path, ok := c.endpoints["ndp"]
+	if !ok {
+		return ndpTable, &APICallError{
+			Endpoint:   "ndp",
+			Message:    "endpoint not found",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "POST", path, strings.NewReader(fetchNdpPayload), &resp); err != nil {
+		return ndpTable, err
+	}
+
+	for _, ndp := range resp.Rows {
+		ndpTable.Ndp = append(ndpTable.Ndp, Ndp{
+			Mac:             ndp.Mac,
+			IP:              ndp.IP,
+			Intf:            ndp.Intf,
+			Manufacturer:    ndp.Manufacturer,
+			IntfDescription: ndp.IntfDescription,
+		})
+	}
+
+	ndpTable.TotalEntries = resp.Total
+
+	return ndpTable, nil
+}
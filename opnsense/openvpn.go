@@ -1,6 +1,9 @@
 package opnsense
 
-import "strings"
+import (
+	"context"
+	"strings"
+)
 
 const fetchOpenVPNPayload = `{"current":1,"rowCount":-1,"sort":{},"searchPhrase":""}`
 
@@ -52,7 +55,7 @@ type OpenVPNSessions struct {
 	Rows []Sessions
 }
 
-func (c *Client) FetchOpenVPNInstances() (OpenVPNInstances, *APICallError) {
+func (c *Client) FetchOpenVPNInstances(ctx context.Context) (OpenVPNInstances, *APICallError) {
 	var resp openVPNSearchResponse
 	var data OpenVPNInstances
 
@@ -65,7 +68,7 @@ func (c *Client) FetchOpenVPNInstances() (OpenVPNInstances, *APICallError) {
 		}
 	}
 
-	if err := c.do("POST", url, strings.NewReader(fetchOpenVPNPayload), &resp); err != nil {
+	if err := c.do(ctx, "POST", url, strings.NewReader(fetchOpenVPNPayload), &resp); err != nil {
 		return data, err
 	}
 
@@ -86,7 +89,7 @@ func (c *Client) FetchOpenVPNInstances() (OpenVPNInstances, *APICallError) {
 	return data, nil
 }
 
-func (c *Client) FetchOpenVPNSessions() (OpenVPNSessions, *APICallError) {
+func (c *Client) FetchOpenVPNSessions(ctx context.Context) (OpenVPNSessions, *APICallError) {
 	var resp openVPNSearchSessionsResponse
 	var data OpenVPNSessions
 
@@ -99,7 +102,7 @@ func (c *Client) FetchOpenVPNSessions() (OpenVPNSessions, *APICallError) {
 		}
 	}
 
-	if err := c.do("GET", url, nil, &resp); err != nil {
+	if err := c.do(ctx, "GET", url, nil, &resp); err != nil {
 		return data, err
 	}
 
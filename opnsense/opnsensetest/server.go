@@ -0,0 +1,237 @@
+// Package opnsensetest provides an OPNsense API server for tests that serves
+// recorded JSON responses of every endpoint of the opnsense.Client,
+// organised per OPNsense release.
+//
+// The responses that are the same on every release are stored once in
+// fixtures/common and are overridden by the responses in fixtures/<release>.
+// A response named <endpoint>.<status code>.json is sent with that status code,
+// e.g. for the endpoints that do not exist on a release.
+package opnsensetest
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/AthennaMind/opnsense-exporter/opnsense"
+)
+
+const (
+	// APIKey is the API key accepted by the server.
+	APIKey = "opnsensetest-key"
+	// APISecret is the API secret accepted by the server.
+	APISecret = "opnsensetest-secret"
+)
+
+// Releases are the OPNsense releases with recorded fixtures.
+var Releases = []string{"24.7", "25.1", "25.7"}
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Fault changes the response of an endpoint.
+type Fault struct {
+	// StatusCode is sent instead of 200 when set.
+	StatusCode int
+	// Latency delays the response. The delay is aborted when the request is cancelled.
+	Latency time.Duration
+	// Body is sent instead of the fixture when set, e.g. to return a malformed response.
+	Body []byte
+}
+
+// Server is an httptest.Server that serves the fixtures of a single OPNsense release.
+// The responses of single endpoints can be changed with SetFault.
+type Server struct {
+	*httptest.Server
+
+	release   string
+	endpoints map[opnsense.EndpointPath]opnsense.EndpointName
+
+	mutex    sync.RWMutex
+	faults   map[opnsense.EndpointName]Fault
+	requests map[opnsense.EndpointName]int
+	queries  map[opnsense.EndpointName]url.Values
+}
+
+// NewServer starts a server with the fixtures of the release.
+// The server is closed when the test finishes.
+func NewServer(t testing.TB, release string) *Server {
+	t.Helper()
+
+	if _, err := fs.Stat(fixtures, path.Join("fixtures", release)); err != nil {
+		t.Fatalf("no fixtures for OPNsense release %s: %v", release, err)
+	}
+
+	s := &Server{
+		release:   release,
+		endpoints: make(map[opnsense.EndpointPath]opnsense.EndpointName),
+		faults:    make(map[opnsense.EndpointName]Fault),
+		requests:  make(map[opnsense.EndpointName]int),
+		queries:   make(map[opnsense.EndpointName]url.Values),
+	}
+
+	client, err := opnsense.NewClient(options.OPNSenseConfig{Protocol: "http"}, "test", slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	for name, path := range client.Endpoints() {
+		s.endpoints[path] = name
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Config returns the settings of an OPNsense client that talks to the server.
+func (s *Server) Config() options.OPNSenseConfig {
+	return options.OPNSenseConfig{
+		Protocol:  "http",
+		Host:      strings.TrimPrefix(s.URL, "http://"),
+		APIKey:    APIKey,
+		APISecret: APISecret,
+	}
+}
+
+// Client returns an OPNsense client that talks to the server.
+func (s *Server) Client(t testing.TB) *opnsense.Client {
+	t.Helper()
+
+	client, err := opnsense.NewClient(s.Config(), "test", slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return &client
+}
+
+// Release returns the OPNsense release the server serves the fixtures of.
+func (s *Server) Release() string {
+	return s.release
+}
+
+// SetFault changes the response of the endpoint until ClearFaults is called.
+func (s *Server) SetFault(endpoint opnsense.EndpointName, fault Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults[endpoint] = fault
+}
+
+// ClearFaults restores the fixture responses of all endpoints.
+func (s *Server) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = make(map[opnsense.EndpointName]Fault)
+}
+
+// Requests returns the number of requests received by the endpoint.
+func (s *Server) Requests(endpoint opnsense.EndpointName) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.requests[endpoint]
+}
+
+// Query returns the query parameters of the last request to the endpoint.
+func (s *Server) Query(endpoint opnsense.EndpointName) url.Values {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.queries[endpoint]
+}
+
+// endpoint returns the endpoint of the request path.
+// Path parameters of the endpoint, e.g. the maximum in
+// api/unbound/overview/totals/10, are ignored.
+func (s *Server) endpoint(requestPath string) (opnsense.EndpointName, bool) {
+	for requestPath != "." && requestPath != "" {
+		if name, ok := s.endpoints[opnsense.EndpointPath(requestPath)]; ok {
+			return name, true
+		}
+		requestPath = path.Dir(requestPath)
+	}
+	return "", false
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.endpoint(strings.TrimPrefix(r.URL.Path, "/"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown endpoint %s", r.URL.Path), http.StatusNotFound)
+		return
+	}
+
+	s.mutex.Lock()
+	s.requests[name]++
+	s.queries[name] = r.URL.Query()
+	fault, faulty := s.faults[name]
+	s.mutex.Unlock()
+
+	if key, secret, ok := r.BasicAuth(); !ok || key != APIKey || secret != APISecret {
+		http.Error(w, `{"status":401,"message":"Authentication Failed"}`, http.StatusUnauthorized)
+		return
+	}
+
+	if faulty && fault.Latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(fault.Latency):
+		}
+	}
+
+	status, body, err := s.fixture(name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("no fixture for endpoint %s", name), http.StatusNotFound)
+		return
+	}
+
+	if faulty {
+		if fault.StatusCode != 0 {
+			status = fault.StatusCode
+		}
+		if fault.Body != nil {
+			body = fault.Body
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// fixture returns the status code and the body of the recorded response of the endpoint.
+func (s *Server) fixture(name opnsense.EndpointName) (int, []byte, error) {
+	if body, err := fixtures.ReadFile(path.Join("fixtures", s.release, string(name)+".json")); err == nil {
+		return http.StatusOK, body, nil
+	}
+
+	matches, err := fs.Glob(fixtures, path.Join("fixtures", s.release, string(name)+".*.json"))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(matches) == 1 {
+		code := strings.TrimSuffix(strings.TrimPrefix(path.Base(matches[0]), string(name)+"."), ".json")
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid status code in fixture %s: %w", matches[0], err)
+		}
+		body, err := fixtures.ReadFile(matches[0])
+		return status, body, err
+	}
+
+	body, err := fixtures.ReadFile(path.Join("fixtures", "common", string(name)+".json"))
+	return http.StatusOK, body, err
+}
@@ -0,0 +1,83 @@
+package opnsensetest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFixturesCoverAllEndpoints(t *testing.T) {
+	for _, release := range Releases {
+		t.Run(release, func(t *testing.T) {
+			server := NewServer(t, release)
+			for _, name := range server.endpoints {
+				if _, _, err := server.fixture(name); err != nil {
+					t.Errorf("missing fixture for endpoint %s: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestUnavailableEndpoint(t *testing.T) {
+	server := NewServer(t, "24.7")
+	client := server.Client(t)
+
+	_, err := client.FetchDnsmasqLeases(context.Background())
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if err.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status code %d, got %d", http.StatusNotFound, err.StatusCode)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	server := NewServer(t, Releases[0])
+	client := server.Client(t)
+
+	if _, err := client.FetchServices(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		fault      Fault
+		statusCode int
+	}{
+		{
+			name:       "Status code",
+			fault:      Fault{StatusCode: http.StatusForbidden, Body: []byte(`{"status":403,"message":"Forbidden"}`)},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Malformed body",
+			fault:      Fault{Body: []byte(`{"rows":[`)},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Latency",
+			fault:      Fault{Latency: time.Second},
+			statusCode: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server.SetFault("services", tc.fault)
+			defer server.ClearFaults()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			_, err := client.FetchServices(ctx)
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if err.StatusCode != tc.statusCode {
+				t.Errorf("expected status code %d, got %d", tc.statusCode, err.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package opnsense
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// pfCounter is a counter of pfctl -si with its total and its rate per second.
+type pfCounter struct {
+	Total numericString `json:"total"`
+	Rate  numericString `json:"rate"`
+}
+
+type pfTable struct {
+	CurrentEntries pfCounter `json:"current-entries"`
+	Searches       pfCounter `json:"searches"`
+	Inserts        pfCounter `json:"inserts"`
+	Removals       pfCounter `json:"removals"`
+}
+
+type pfInfoResponse struct {
+	Info struct {
+		StateTable          pfTable              `json:"state-table"`
+		SourceTrackingTable pfTable              `json:"source-tracking-table"`
+		Counters            map[string]pfCounter `json:"counters"`
+		LimitCounters       map[string]pfCounter `json:"limit-counters"`
+	} `json:"info"`
+}
+
+type pfMemoryResponse struct {
+	Memory map[string]struct {
+		HardLimit numericString `json:"hard limit"`
+	} `json:"memory"`
+}
+
+type pfTimeoutsResponse struct {
+	Timeouts map[string]string `json:"timeouts"`
+}
+
+// PFTable holds the entries and the operations of a pf table (states or source nodes).
+type PFTable struct {
+	Entries  float64
+	Searches float64
+	Inserts  float64
+	Removals float64
+}
+
+// PFInfo holds the state of the pf packet filter.
+type PFInfo struct {
+	States   PFTable
+	SrcNodes PFTable
+	// Counters holds the number of packets by reason (match, memory, congestion, state-mismatch, ...)
+	Counters map[string]float64
+	// LimitCounters holds the number of times a limit was hit by limit (max-states-per-rule, max-src-conn, ...)
+	LimitCounters map[string]float64
+	// Limits holds the hard limits of the memory pools (states, src-nodes, frags, table-entries)
+	Limits map[string]float64
+	// Timeouts holds the timeouts in seconds by name (tcp.established, udp.first, ...)
+	Timeouts map[string]float64
+}
+
+// FetchPFInfo fetches the state and source node tables, counters, memory limits and timeouts of pf.
+func (c *Client) FetchPFInfo(ctx context.Context) (PFInfo, *APICallError) {
+	var infoResp pfInfoResponse
+	var memoryResp pfMemoryResponse
+	var timeoutsResp pfTimeoutsResponse
+	data := PFInfo{
+		Counters:      make(map[string]float64),
+		LimitCounters: make(map[string]float64),
+		Limits:        make(map[string]float64),
+		Timeouts:      make(map[string]float64),
+	}
+
+	requests := []struct {
+		name EndpointName
+		resp any
+	}{
+		{"pfStatisticsInfo", &infoResp},
+		{"pfStatisticsMemory", &memoryResp},
+		{"pfStatisticsTimeouts", &timeoutsResp},
+	}
+	for _, request := range requests {
+		path, ok := c.endpoints[request.name]
+		if !ok {
+			return data, &APICallError{
+				Endpoint:   string(request.name),
+				Message:    "endpoint not found in client endpoints",
+				StatusCode: 0,
+			}
+		}
+		if err := c.do(ctx, "GET", path, nil, request.resp); err != nil {
+			return data, err
+		}
+	}
+
+	infoPath := c.endpoints["pfStatisticsInfo"]
+	var err *APICallError
+	if data.States, err = parsePFTable(infoResp.Info.StateTable, infoPath); err != nil {
+		return data, err
+	}
+	if data.SrcNodes, err = parsePFTable(infoResp.Info.SourceTrackingTable, infoPath); err != nil {
+		return data, err
+	}
+	for reason, counter := range infoResp.Info.Counters {
+		if data.Counters[reason], err = parseStringToFloat(string(counter.Total), infoPath); err != nil {
+			return data, err
+		}
+	}
+	for limit, counter := range infoResp.Info.LimitCounters {
+		if data.LimitCounters[limit], err = parseStringToFloat(string(counter.Total), infoPath); err != nil {
+			return data, err
+		}
+	}
+
+	memoryPath := c.endpoints["pfStatisticsMemory"]
+	for pool, memory := range memoryResp.Memory {
+		if data.Limits[pool], err = parseStringToFloat(string(memory.HardLimit), memoryPath); err != nil {
+			return data, err
+		}
+	}
+
+	// the adaptive timeouts are a number of states, not a duration
+	for name, timeout := range timeoutsResp.Timeouts {
+		seconds, ok := strings.CutSuffix(strings.TrimSpace(timeout), "s")
+		if !ok {
+			continue
+		}
+		value, parseErr := strconv.ParseFloat(seconds, 64)
+		if parseErr != nil {
+			continue
+		}
+		data.Timeouts[name] = value
+	}
+
+	return data, nil
+}
+
+func parsePFTable(table pfTable, endpoint EndpointPath) (PFTable, *APICallError) {
+	var data PFTable
+	var err *APICallError
+
+	if data.Entries, err = parseStringToFloat(string(table.CurrentEntries.Total), endpoint); err != nil {
+		return data, err
+	}
+	if data.Searches, err = parseStringToFloat(string(table.Searches.Total), endpoint); err != nil {
+		return data, err
+	}
+	if data.Inserts, err = parseStringToFloat(string(table.Inserts.Total), endpoint); err != nil {
+		return data, err
+	}
+	if data.Removals, err = parseStringToFloat(string(table.Removals.Total), endpoint); err != nil {
+		return data, err
+	}
+	return data, nil
+}
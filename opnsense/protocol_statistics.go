@@ -1,5 +1,7 @@
 package opnsense
 
+import "context"
+
 type protocolStatisticsResponse struct {
 	Statistics struct {
 		TCP struct {
@@ -264,7 +266,7 @@ type ProtocolStatistics struct {
 	UDPDroppedByReason        map[string]int
 }
 
-func (c *Client) FetchProtocolStatistics() (ProtocolStatistics, *APICallError) {
+func (c *Client) FetchProtocolStatistics(ctx context.Context) (ProtocolStatistics, *APICallError) {
 	var resp protocolStatisticsResponse
 	url, ok := c.endpoints["protocolStatistics"]
 	if !ok {
@@ -274,7 +276,7 @@ func (c *Client) FetchProtocolStatistics() (ProtocolStatistics, *APICallError) {
 			Message:    "endpoint not found in client endpoints",
 		}
 	}
-	if err := c.do("GET", url, nil, &resp); err != nil {
+	if err := c.do(ctx, "GET", url, nil, &resp); err != nil {
 		return ProtocolStatistics{}, err
 	}
 
@@ -0,0 +1,75 @@
+package opnsense
+
+import (
+	"context"
+	"strings"
+)
+
+type routesResponse []struct {
+	Proto       string `json:"proto"`
+	Destination string `json:"destination"`
+	Gateway     string `json:"gateway"`
+	Flags       string `json:"flags"`
+	Netif       string `json:"netif"`
+}
+
+// Route holds an entry of the routing table.
+type Route struct {
+	// Family is the address family of the route, ipv4 or ipv6
+	Family      string
+	Destination string
+	Gateway     string
+	// Interface is the device of the route
+	Interface string
+	Flags     string
+	// Default is true for the default route of the address family
+	Default bool
+	Static  bool
+	// ViaGateway is true if the destination is reached through a gateway
+	ViaGateway bool
+	Host       bool
+}
+
+// FetchRoutes fetches the routing table.
+func (c *Client) FetchRoutes(ctx context.Context) ([]Route, *APICallError) {
+	var resp routesResponse
+	var data []Route
+
+	path, ok := c.endpoints["routes"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "routes",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	if err := c.do(ctx, "GET", path, nil, &resp); err != nil {
+		return data, err
+	}
+
+	for _, route := range resp {
+		data = append(data, Route{
+			Family:      route.Proto,
+			Destination: route.Destination,
+			Gateway:     route.Gateway,
+			Interface:   route.Netif,
+			Flags:       route.Flags,
+			Default:     isDefaultRoute(route.Destination),
+			Static:      strings.Contains(route.Flags, "S"),
+			ViaGateway:  strings.Contains(route.Flags, "G"),
+			Host:        strings.Contains(route.Flags, "H"),
+		})
+	}
+
+	return data, nil
+}
+
+// isDefaultRoute reports whether the destination is the default route of its address family.
+func isDefaultRoute(destination string) bool {
+	switch destination {
+	case "default", "0.0.0.0/0", "::/0":
+		return true
+	}
+	return false
+}
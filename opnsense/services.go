@@ -1,5 +1,7 @@
 package opnsense
 
+import "context"
+
 type servicesSearchResponse struct {
 	Rows []struct {
 		ID          string `json:"id"`
@@ -33,7 +35,7 @@ type Services struct {
 	TotalStopped int
 }
 
-func (c *Client) FetchServices() (Services, *APICallError) {
+func (c *Client) FetchServices(ctx context.Context) (Services, *APICallError) {
 	var resp servicesSearchResponse
 	var services Services
 
@@ -46,7 +48,7 @@ func (c *Client) FetchServices() (Services, *APICallError) {
 			StatusCode: 0,
 		}
 	}
-	err := c.do("GET", url, nil, &resp)
+	err := c.do(ctx, "GET", url, nil, &resp)
 	if err != nil {
 		return services, err
 	}
@@ -1,134 +1,392 @@
 package opnsense
 
-type systemInfoResponse struct {
-	System  string   `json:"system"`
-	Plugins []string `json:"plugins"`
-	Data    struct {
-		Interfaces []struct {
-			Inpkts       string `json:"inpkts"`
-			Outpkts      string `json:"outpkts"`
-			Inbytes      string `json:"inbytes"`
-			Outbytes     string `json:"outbytes"`
-			InbytesFrmt  string `json:"inbytes_frmt"`
-			OutbytesFrmt string `json:"outbytes_frmt"`
-			Inerrs       string `json:"inerrs"`
-			Outerrs      string `json:"outerrs"`
-			Collisions   string `json:"collisions"`
-			Descr        string `json:"descr"`
-			Name         string `json:"name"`
-			Status       string `json:"status"`
-			Ipaddr       string `json:"ipaddr"`
-			Media        string `json:"media"`
-		} `json:"interfaces"`
-		System struct {
-			Versions []string `json:"versions"`
-			CPU      struct {
-				Used          string   `json:"used"`
-				User          string   `json:"user"`
-				Nice          string   `json:"nice"`
-				Sys           string   `json:"sys"`
-				Intr          string   `json:"intr"`
-				Idle          string   `json:"idle"`
-				Model         string   `json:"model"`
-				Cpus          string   `json:"cpus"`
-				Cores         string   `json:"cores"`
-				MaxFreq       string   `json:"max.freq"`
-				CurFreq       string   `json:"cur.freq"`
-				FreqTranslate string   `json:"freq_translate"`
-				Load          []string `json:"load"`
-			} `json:"cpu"`
-			DateFrmt string `json:"date_frmt"`
-			DateTime string `json:"date_time"`
-			Uptime   string `json:"uptime"`
-			Config   struct {
-				LastChange     string `json:"last_change"`
-				LastChangeFrmt string `json:"last_change_frmt"`
-			} `json:"config"`
-			Kernel struct {
-				Pf struct {
-					Maxstates string `json:"maxstates"`
-					States    string `json:"states"`
-				} `json:"pf"`
-				Mbuf struct {
-					Total string `json:"total"`
-					Max   string `json:"max"`
-				} `json:"mbuf"`
-				Memory struct {
-					Total  string `json:"total"`
-					Used   string `json:"used"`
-					Arc    string `json:"arc"`
-					ArcTxt string `json:"arc_txt"`
-				} `json:"memory"`
-			} `json:"kernel"`
-			Disk struct {
-				Swap []struct {
-					Device string `json:"device"`
-					Total  string `json:"total"`
-					Used   string `json:"used"`
-				} `json:"swap"`
-				Devices []struct {
-					Device     string `json:"device"`
-					Type       string `json:"type"`
-					Size       string `json:"size"`
-					Used       string `json:"used"`
-					Available  string `json:"available"`
-					Capacity   string `json:"capacity"`
-					Mountpoint string `json:"mountpoint"`
-				} `json:"devices"`
-			} `json:"disk"`
-			Firmware string `json:"firmware"`
-		} `json:"system"`
-		Temperature []struct {
-			Device         string `json:"device"`
-			DeviceSeq      string `json:"device_seq"`
-			Temperature    string `json:"temperature"`
-			Type           string `json:"type"`
-			TypeTranslated string `json:"type_translated"`
-		} `json:"temperature"`
-	} `json:"data"`
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type systemResourcesResponse struct {
+	Memory struct {
+		Total numericString `json:"total"`
+		Used  numericString `json:"used"`
+		Arc   numericString `json:"arc"`
+	} `json:"memory"`
+}
+
+type systemDiskResponse struct {
+	Devices []struct {
+		Device     string `json:"device"`
+		Type       string `json:"type"`
+		Blocks     string `json:"blocks"`
+		Used       string `json:"used"`
+		Available  string `json:"available"`
+		UsedPct    string `json:"used_pct"`
+		Mountpoint string `json:"mountpoint"`
+	} `json:"devices"`
+}
+
+type systemSwapResponse struct {
+	Swap []struct {
+		Device string        `json:"device"`
+		Total  numericString `json:"total"`
+		Used   numericString `json:"used"`
+	} `json:"swap"`
+}
+
+type systemTimeResponse struct {
+	Uptime   string `json:"uptime"`
+	Datetime string `json:"datetime"`
+	Boottime string `json:"boottime"`
+	Config   string `json:"config"`
+	Loadavg  string `json:"loadavg"`
+}
+
+type systemActivityResponse struct {
+	Headers []string `json:"headers"`
 }
 
+type systemTemperatureResponse []struct {
+	Device         string `json:"device"`
+	DeviceSeq      string `json:"device_seq"`
+	Temperature    string `json:"temperature"`
+	Type           string `json:"type"`
+	TypeTranslated string `json:"type_translated"`
+}
+
+// Temperature is the reading of a hardware temperature sensor.
 type Temperature struct {
-	Device                string
-	DeviceSeq             string
-	Type                  string
-	TemperatureCelsuis    int
-	TemperatureFahrenheit float32
+	Device    string
+	DeviceSeq string
+	Type      string
+	Celsius   float64
+}
+
+// Filesystem is a mounted filesystem with its sizes in bytes.
+type Filesystem struct {
+	Device     string
+	Type       string
+	Mountpoint string
+	Size       float64
+	Used       float64
+	Available  float64
+}
+
+// SwapDevice is a swap device with its sizes in bytes.
+type SwapDevice struct {
+	Device string
+	Total  float64
+	Used   float64
+}
+
+// SystemMemory holds the memory usage of the OPNsense system in bytes.
+type SystemMemory struct {
+	Total float64
+	Used  float64
+	// ArcSize is the size of the ZFS ARC, -1 on systems without ZFS.
+	ArcSize float64
 }
 
-type SystemInfo struct {
-	Temperature []Temperature
+// SystemTime holds the load and the uptime of the OPNsense system.
+type SystemTime struct {
+	// Load holds the 1, 5 and 15 minute load averages.
+	Load   [3]float64
+	Uptime time.Duration
+	// BootTime is parsed from the boot time reported by OPNsense,
+	// so it stays the same between the scrapes.
+	BootTime time.Time
 }
 
-func (c *Client) FetchSystemInfo() (SystemInfo, *APICallError) {
-	var resp systemInfoResponse
-	var data SystemInfo
+// systemTimeLayout is the layout of the times returned by the systemTime endpoint,
+// e.g. "Tue Jul 30 6:07:58 UTC 2024".
+const systemTimeLayout = "Mon Jan _2 15:04:05 MST 2006"
 
-	url, ok := c.endpoints["systemInfo"]
+// maxTimeZoneOffset is the largest offset of a time zone from UTC.
+const maxTimeZoneOffset = 14 * time.Hour
 
+var (
+	// uptimeRegex matches the uptime returned by the systemTime endpoint, e.g. "2 days 03:04:05".
+	uptimeRegex = regexp.MustCompile(`^(?:(\d+) days?,? *)?(\d+):(\d{2}):(\d{2})$`)
+	// cpuRegex matches a single mode of the CPU line of top, e.g. "1.2% system".
+	cpuRegex = regexp.MustCompile(`([\d.]+)% (\w+)`)
+)
+
+// fetchSystemEndpoint sends a GET request to the system endpoint and decodes the response.
+func (c *Client) fetchSystemEndpoint(ctx context.Context, name EndpointName, resp any) (EndpointPath, *APICallError) {
+	path, ok := c.endpoints[name]
+	if !ok {
+		return path, &APICallError{
+			Endpoint:   string(name),
+			Message:    "endpoint not found",
+			StatusCode: 0,
+		}
+	}
+	return path, c.do(ctx, "GET", path, nil, resp)
+}
+
+// FetchSystemMemory fetches the memory usage of the OPNsense system.
+func (c *Client) FetchSystemMemory(ctx context.Context) (SystemMemory, *APICallError) {
+	var resp systemResourcesResponse
+	data := SystemMemory{ArcSize: -1}
+
+	path, err := c.fetchSystemEndpoint(ctx, "systemResources", &resp)
+	if err != nil {
+		return data, err
+	}
+
+	if data.Total, err = parseStringToFloat(string(resp.Memory.Total), path); err != nil {
+		return data, err
+	}
+	if data.Used, err = parseStringToFloat(string(resp.Memory.Used), path); err != nil {
+		return data, err
+	}
+	if resp.Memory.Arc != "" {
+		if data.ArcSize, err = parseStringToFloat(string(resp.Memory.Arc), path); err != nil {
+			return data, err
+		}
+	}
+
+	return data, nil
+}
+
+// FetchFilesystems fetches the mounted filesystems of the OPNsense system.
+// Pseudo filesystems without a size, like devfs, are left out.
+func (c *Client) FetchFilesystems(ctx context.Context) ([]Filesystem, *APICallError) {
+	var resp systemDiskResponse
+	var data []Filesystem
+
+	path, err := c.fetchSystemEndpoint(ctx, "systemDisk", &resp)
+	if err != nil {
+		return data, err
+	}
+
+	for _, device := range resp.Devices {
+		var fs Filesystem
+		if fs.Size, err = parseSize(device.Blocks, path); err != nil {
+			return data, err
+		}
+		if fs.Size == 0 {
+			continue
+		}
+		if fs.Used, err = parseSize(device.Used, path); err != nil {
+			return data, err
+		}
+		if fs.Available, err = parseSize(device.Available, path); err != nil {
+			return data, err
+		}
+		fs.Device = device.Device
+		fs.Type = device.Type
+		fs.Mountpoint = device.Mountpoint
+		data = append(data, fs)
+	}
+
+	return data, nil
+}
+
+// FetchSwap fetches the swap devices of the OPNsense system.
+func (c *Client) FetchSwap(ctx context.Context) ([]SwapDevice, *APICallError) {
+	var resp systemSwapResponse
+	var data []SwapDevice
+
+	path, err := c.fetchSystemEndpoint(ctx, "systemSwap", &resp)
+	if err != nil {
+		return data, err
+	}
+
+	// swapinfo reports the sizes in KiB
+	for _, device := range resp.Swap {
+		swap := SwapDevice{Device: device.Device}
+		if swap.Total, err = parseStringToFloat(string(device.Total), path); err != nil {
+			return data, err
+		}
+		if swap.Used, err = parseStringToFloat(string(device.Used), path); err != nil {
+			return data, err
+		}
+		swap.Total *= 1024
+		swap.Used *= 1024
+		data = append(data, swap)
+	}
+
+	return data, nil
+}
+
+// FetchSystemTime fetches the load averages and the uptime of the OPNsense system.
+func (c *Client) FetchSystemTime(ctx context.Context) (SystemTime, *APICallError) {
+	var resp systemTimeResponse
+	var data SystemTime
+
+	path, err := c.fetchSystemEndpoint(ctx, "systemTime", &resp)
+	if err != nil {
+		return data, err
+	}
+
+	loads := strings.Split(resp.Loadavg, ",")
+	if len(loads) != 3 {
+		return data, &APICallError{
+			Endpoint:   string(path),
+			Message:    fmt.Sprintf("unexpected load averages '%s'", resp.Loadavg),
+			StatusCode: 0,
+		}
+	}
+	for i, load := range loads {
+		if data.Load[i], err = parseStringToFloat(strings.TrimSpace(load), path); err != nil {
+			return data, err
+		}
+	}
+	if data.Uptime, err = parseUptime(resp.Uptime, path); err != nil {
+		return data, err
+	}
+	if data.BootTime, err = parseBootTime(resp.Boottime, resp.Datetime, time.Now(), path); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// FetchCPUUtilization fetches the CPU utilisation ratio (0-1) of the OPNsense system
+// by mode (user, nice, system, interrupt, idle).
+func (c *Client) FetchCPUUtilization(ctx context.Context) (map[string]float64, *APICallError) {
+	var resp systemActivityResponse
+	data := make(map[string]float64)
+
+	if _, err := c.fetchSystemEndpoint(ctx, "systemActivity", &resp); err != nil {
+		return data, err
+	}
+
+	for _, header := range resp.Headers {
+		if !strings.HasPrefix(header, "CPU:") {
+			continue
+		}
+		for _, match := range cpuRegex.FindAllStringSubmatch(header, -1) {
+			percent, err := strconv.ParseFloat(match[1], 64)
+			if err != nil {
+				continue
+			}
+			data[match[2]] = percent / 100
+		}
+		break
+	}
+
+	return data, nil
+}
+
+// parseUptime parses the uptime returned by the systemTime endpoint, e.g. "2 days 03:04:05".
+func parseUptime(value string, endpoint EndpointPath) (time.Duration, *APICallError) {
+	match := uptimeRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, &APICallError{
+			Endpoint:   string(endpoint),
+			Message:    fmt.Sprintf("unexpected uptime '%s'", value),
+			StatusCode: 0,
+		}
+	}
+
+	var parts [4]int
+	for i, part := range match[1:] {
+		if part != "" {
+			parts[i], _ = strconv.Atoi(part)
+		}
+	}
+
+	return time.Duration(parts[0])*24*time.Hour +
+		time.Duration(parts[1])*time.Hour +
+		time.Duration(parts[2])*time.Minute +
+		time.Duration(parts[3])*time.Second, nil
+}
+
+// parseBootTime parses the boot time returned by the systemTime endpoint.
+// Time zone abbreviations other than UTC are parsed with a zero offset, so the
+// offset of the OPNsense time zone is derived from the difference between its
+// current time and now, rounded to 15 minutes to keep the boot time stable.
+// Differences larger than any time zone offset are clock errors and ignored.
+func parseBootTime(boottime, datetime string, now time.Time, endpoint EndpointPath) (time.Time, *APICallError) {
+	boot, err := time.ParseInLocation(systemTimeLayout, strings.TrimSpace(boottime), time.UTC)
+	if err != nil {
+		return time.Time{}, &APICallError{
+			Endpoint:   string(endpoint),
+			Message:    fmt.Sprintf("unexpected boot time '%s'", boottime),
+			StatusCode: 0,
+		}
+	}
+	current, err := time.ParseInLocation(systemTimeLayout, strings.TrimSpace(datetime), time.UTC)
+	if err != nil {
+		return time.Time{}, &APICallError{
+			Endpoint:   string(endpoint),
+			Message:    fmt.Sprintf("unexpected date and time '%s'", datetime),
+			StatusCode: 0,
+		}
+	}
+
+	offset := current.Sub(now).Round(15 * time.Minute)
+	if offset.Abs() > maxTimeZoneOffset {
+		offset = 0
+	}
+
+	return boot.Add(-offset), nil
+}
+
+// sizeUnits holds the multipliers of the units of df -h.
+var sizeUnits = map[byte]float64{
+	'B': 1,
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+	'P': 1 << 50,
+}
+
+// parseSize parses a size in bytes with an optional unit of df -h, e.g. "7.7G".
+func parseSize(value string, endpoint EndpointPath) (float64, *APICallError) {
+	value = strings.TrimSpace(value)
+	multiplier := 1.0
+	if value != "" {
+		if unit, ok := sizeUnits[value[len(value)-1]]; ok {
+			multiplier = unit
+			value = value[:len(value)-1]
+		}
+	}
+
+	size, err := parseStringToFloat(value, endpoint)
+	if err != nil {
+		return 0, err
+	}
+	return size * multiplier, nil
+}
+
+// FetchTemperatures fetches the readings of the CPU, ACPI and disk temperature sensors.
+func (c *Client) FetchTemperatures(ctx context.Context) ([]Temperature, *APICallError) {
+	var resp systemTemperatureResponse
+	var data []Temperature
+
+	path, ok := c.endpoints["systemTemperature"]
 	if !ok {
 		return data, &APICallError{
-			Endpoint:   "systemInfo",
+			Endpoint:   "systemTemperature",
 			Message:    "endpoint not found",
 			StatusCode: 0,
 		}
 	}
 
-	if err := c.do("GET", url, nil, &resp); err != nil {
+	if err := c.do(ctx, "GET", path, nil, &resp); err != nil {
 		return data, err
 	}
 
-	for _, v := range resp.Data.Temperature {
-		celsius, err := parseStringToInt(v.Temperature, url)
+	// malformed readings only leave out the sensor, not all sensors
+	for _, sensor := range resp {
+		celsius, err := strconv.ParseFloat(strings.TrimSpace(sensor.Temperature), 64)
 		if err != nil {
-			return data, err
+			c.log.Warn("failed to parse the temperature of the sensor",
+				"component", "opnsense-client", "device", sensor.Device, "temperature", sensor.Temperature, "err", err)
+			continue
 		}
-		data.Temperature = append(data.Temperature, Temperature{
-			Device:                v.Device,
-			DeviceSeq:             v.DeviceSeq,
-			Type:                  v.Type,
-			TemperatureCelsuis:    celsius,
-			TemperatureFahrenheit: (float32(celsius) * 1.8) + 32,
+		data = append(data, Temperature{
+			Device:    sensor.Device,
+			DeviceSeq: sensor.DeviceSeq,
+			Type:      sensor.Type,
+			Celsius:   celsius,
 		})
 	}
 
@@ -0,0 +1,110 @@
+package opnsense
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUptime(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{name: "Days", value: "2 days 03:04:05", expected: 51*time.Hour + 4*time.Minute + 5*time.Second},
+		{name: "Single day with comma", value: "1 day, 00:00:10", expected: 24*time.Hour + 10*time.Second},
+		{name: "No days", value: "12:30:00", expected: 12*time.Hour + 30*time.Minute},
+		{name: "Invalid", value: "up 3 mins", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseUptime(tc.value, "test")
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected an error for %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("parseUptime(%q) = %v, expected %v", tc.value, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseBootTime(t *testing.T) {
+	now := time.Date(2024, time.August, 1, 9, 12, 3, 0, time.UTC)
+	expected := time.Date(2024, time.July, 30, 6, 7, 58, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		boottime  string
+		datetime  string
+		expected  time.Time
+		expectErr bool
+	}{
+		{name: "UTC", boottime: "Tue Jul 30 6:07:58 UTC 2024", datetime: "Thu Aug 1 9:12:03 UTC 2024", expected: expected},
+		{name: "Padded day", boottime: "Tue Jul 30 06:07:58 UTC 2024", datetime: "Thu Aug  1 09:12:03 UTC 2024", expected: expected},
+		{name: "Time zone abbreviation", boottime: "Tue Jul 30 8:07:58 CEST 2024", datetime: "Thu Aug 1 11:12:03 CEST 2024", expected: expected},
+		{name: "Clock skew", boottime: "Tue Jul 30 6:07:58 UTC 2024", datetime: "Thu Aug 1 9:13:31 UTC 2024", expected: expected},
+		{name: "Clock error", boottime: "Tue Jul 30 6:07:58 UTC 2024", datetime: "Thu Jan 1 0:00:00 UTC 1970", expected: expected},
+		{name: "Invalid boot time", boottime: "yesterday", datetime: "Thu Aug 1 9:12:03 UTC 2024", expectErr: true},
+		{name: "Invalid date and time", boottime: "Tue Jul 30 6:07:58 UTC 2024", datetime: "now", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseBootTime(tc.boottime, tc.datetime, now, "test")
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected an error for %q", tc.boottime)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("parseBootTime(%q, %q) = %s, expected %s", tc.boottime, tc.datetime, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  float64
+		expectErr bool
+	}{
+		{name: "Bytes", value: "512", expected: 512},
+		{name: "Bytes with unit", value: "512B", expected: 512},
+		{name: "Kibibytes", value: "4.0K", expected: 4096},
+		{name: "Gibibytes", value: "1.5G", expected: 1.5 * (1 << 30)},
+		{name: "Invalid", value: "many", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseSize(tc.value, "test")
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected an error for %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("parseSize(%q) = %v, expected %v", tc.value, got, tc.expected)
+			}
+		})
+	}
+}
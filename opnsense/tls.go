@@ -0,0 +1,67 @@
+package opnsense
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/prometheus/common/config"
+)
+
+// newTransport builds the HTTP transport for the connection to the OPNsense API.
+// When a CA file or a client certificate is configured, the files are re-read
+// on every request and the transport is rebuilt when one of them changed.
+func newTransport(cfg options.OPNSenseConfig) (http.RoundTripper, error) {
+	newRoundTripper := func(tlsConfig *tls.Config) (http.RoundTripper, error) {
+		return &http.Transport{
+			TLSClientConfig:       tlsConfig,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   3 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
+		}, nil
+	}
+
+	if cfg.TLS.CAFile == "" && cfg.TLS.CertFile == "" {
+		sslPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to load system cert pool"), err)
+		}
+
+		return newRoundTripper(&tls.Config{
+			InsecureSkipVerify: cfg.Insecure,
+			RootCAs:            sslPool,
+			ServerName:         cfg.TLS.ServerName,
+			MinVersion:         uint16(config.TLSVersions[cfg.TLS.MinVersion]),
+		})
+	}
+
+	tlsConfig, err := config.NewTLSConfig(&config.TLSConfig{
+		CAFile:             cfg.TLS.CAFile,
+		CertFile:           cfg.TLS.CertFile,
+		KeyFile:            cfg.TLS.KeyFile,
+		ServerName:         cfg.TLS.ServerName,
+		InsecureSkipVerify: cfg.Insecure,
+		MinVersion:         config.TLSVersions[cfg.TLS.MinVersion],
+	})
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to load tls config"), err)
+	}
+
+	var settings config.TLSRoundTripperSettings
+	if cfg.TLS.CAFile != "" {
+		settings.CA = config.NewFileSecret(cfg.TLS.CAFile)
+	}
+	if cfg.TLS.CertFile != "" {
+		settings.Cert = config.NewFileSecret(cfg.TLS.CertFile)
+		settings.Key = config.NewFileSecret(cfg.TLS.KeyFile)
+	}
+
+	return config.NewTLSRoundTripper(tlsConfig, settings, newRoundTripper)
+}
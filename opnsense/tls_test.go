@@ -0,0 +1,84 @@
+package opnsense
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AthennaMind/opnsense-exporter/internal/options"
+	"github.com/prometheus/common/promslog"
+)
+
+func TestTLSCAFileReload(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"System":{"status":"OK"}}`))
+	}))
+	defer server.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "unrelated CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	unrelatedCA, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: unrelatedCA}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The certificate of httptest servers is issued for example.com and 127.0.0.1,
+	// the server name override is verified by connecting through "localhost".
+	host := strings.Replace(strings.TrimPrefix(server.URL, "https://"), "127.0.0.1", "localhost", 1)
+
+	client, err := NewClient(options.OPNSenseConfig{
+		Protocol:  "https",
+		Host:      host,
+		APIKey:    "key",
+		APISecret: "secret",
+		TLS: options.TLSConfig{
+			CAFile:     caFile,
+			ServerName: "example.com",
+			MinVersion: "TLS12",
+		},
+	}, "test", promslog.NewNopLogger())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := client.HealthCheck(context.Background()); err == nil {
+		t.Fatalf("expected certificate verification error with the wrong CA, got nil")
+	}
+
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, serverCA, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.HealthCheck(context.Background()); err != nil {
+		t.Errorf("expected no error after the CA file changed, got %v", err)
+	}
+}
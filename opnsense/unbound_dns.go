@@ -1,6 +1,7 @@
 package opnsense
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
@@ -57,19 +58,9 @@ type unboundDNSStatusResponse struct {
 			Elapsed string `json:"elapsed"`
 		} `json:"time"`
 		Mem struct {
-			Cache struct {
-				Rrset                string `json:"rrset"`
-				Message              string `json:"message"`
-				DnscryptSharedSecret string `json:"dnscrypt_shared_secret"`
-				DnscryptNonce        string `json:"dnscrypt_nonce"`
-			} `json:"cache"`
-			Mod struct {
-				Iterator  string `json:"iterator"`
-				Validator string `json:"validator"`
-				Respip    string `json:"respip"`
-				Dynlibmod string `json:"dynlibmod"`
-			} `json:"mod"`
-			Streamwait string `json:"streamwait"`
+			Cache      map[string]string `json:"cache"`
+			Mod        map[string]string `json:"mod"`
+			Streamwait string            `json:"streamwait"`
 			HTTP       struct {
 				QueryBuffer    string `json:"query_buffer"`
 				ResponseBuffer string `json:"response_buffer"`
@@ -77,17 +68,7 @@ type unboundDNSStatusResponse struct {
 		} `json:"mem"`
 		Num struct {
 			Query struct {
-				Type struct {
-					A     string `json:"A"`
-					Soa   string `json:"SOA"`
-					Ptr   string `json:"PTR"`
-					Mx    string `json:"MX"`
-					Txt   string `json:"TXT"`
-					Aaaa  string `json:"AAAA"`
-					Srv   string `json:"SRV"`
-					Svcb  string `json:"SVCB"`
-					HTTPS string `json:"HTTPS"`
-				} `json:"type"`
+				Type  map[string]string `json:"type"`
 				Class struct {
 					In string `json:"IN"`
 				} `json:"class"`
@@ -134,17 +115,9 @@ type unboundDNSStatusResponse struct {
 				} `json:"authzone"`
 			} `json:"query"`
 			Answer struct {
-				Rcode struct {
-					Noerror  string `json:"NOERROR"`
-					Formerr  string `json:"FORMERR"`
-					Servfail string `json:"SERVFAIL"`
-					Nxdomain string `json:"NXDOMAIN"`
-					Notimpl  string `json:"NOTIMPL"`
-					Refused  string `json:"REFUSED"`
-					Nodata   string `json:"nodata"`
-				} `json:"rcode"`
-				Secure string `json:"secure"`
-				Bogus  string `json:"bogus"`
+				Rcode  map[string]string `json:"rcode"`
+				Secure string            `json:"secure"`
+				Bogus  string            `json:"bogus"`
 			} `json:"answer"`
 			Rrset struct {
 				Bogus string `json:"bogus"`
@@ -189,24 +162,81 @@ type unboundDNSStatusResponse struct {
 	} `json:"data"`
 }
 
+// UnboundDNSOverview holds the statistics of the Unbound DNS resolver.
+// The counters are totals since the start of Unbound.
 type UnboundDNSOverview struct {
-	AnswerRcodes      map[string]int
-	QueryTypes        map[string]int
-	Total             int
-	BlocklistSize     int
-	Passed            int
-	AnswerRcodesTotal int
-	AnnswerBogusTotal int
-	AnswerSecureTotal int
-	UptimeSeconds     float64
+	// QueryTypes holds the number of queries by query type (A, AAAA, ...)
+	QueryTypes map[string]float64
+	// AnswerRcodes holds the number of answers by rcode (NOERROR, NXDOMAIN, ...)
+	AnswerRcodes map[string]float64
+	// AnswerNoData is the number of NOERROR answers without data
+	AnswerNoData      float64
+	AnswerSecureTotal float64
+	AnswerBogusTotal  float64
+	RrsetBogusTotal   float64
+
+	Queries              float64
+	QueriesIPRatelimited float64
+	QueriesTimedOut      float64
+	CacheHits            float64
+	CacheMisses          float64
+	Prefetch             float64
+	Expired              float64
+	RecursiveReplies     float64
+
+	// RecursionTimeAvg and RecursionTimeMedian are in seconds
+	RecursionTimeAvg    float64
+	RecursionTimeMedian float64
+
+	RequestListAvg         float64
+	RequestListMax         float64
+	RequestListOverwritten float64
+	RequestListExceeded    float64
+	RequestListCurrentAll  float64
+	RequestListCurrentUser float64
+
+	// MemoryCaches holds the memory used by each cache (rrset, message, ...) in bytes
+	MemoryCaches map[string]float64
+	// MemoryModules holds the memory used by each module (iterator, validator, ...) in bytes
+	MemoryModules map[string]float64
+	// CacheEntries holds the number of entries in each cache (msg, rrset, infra, key)
+	CacheEntries map[string]float64
+
+	UptimeSeconds float64
+}
+
+// unboundValueParser parses the statistics of Unbound and keeps the first error.
+// Statistics that are not reported by the running Unbound version are 0.
+type unboundValueParser struct {
+	url EndpointPath
+	err *APICallError
+}
+
+func (p *unboundValueParser) parse(value string) float64 {
+	if value == "" || p.err != nil {
+		return 0
+	}
+	parsed, err := parseStringToFloat(value, p.url)
+	if err != nil {
+		p.err = err
+	}
+	return parsed
+}
+
+func (p *unboundValueParser) parseMap(values map[string]string) map[string]float64 {
+	parsed := make(map[string]float64, len(values))
+	for key, value := range values {
+		if value != "" {
+			parsed[key] = p.parse(value)
+		}
+	}
+	return parsed
 }
 
-func (c *Client) FetchUnboundOverview() (UnboundDNSOverview, *APICallError) {
+func (c *Client) FetchUnboundOverview(ctx context.Context) (UnboundDNSOverview, *APICallError) {
 	var (
-		response      unboundDNSStatusResponse
-		data          UnboundDNSOverview
-		err           error
-		errConvertion *APICallError
+		response unboundDNSStatusResponse
+		data     UnboundDNSOverview
 	)
 
 	url, ok := c.endpoints["unboundDNSStatus"]
@@ -217,13 +247,11 @@ func (c *Client) FetchUnboundOverview() (UnboundDNSOverview, *APICallError) {
 			StatusCode: 0,
 		}
 	}
-	if err := c.do("GET", url, nil, &response); err != nil {
+	if err := c.do(ctx, "GET", url, nil, &response); err != nil {
 		return data, err
 	}
 
-	data.QueryTypes = make(map[string]int)
-	data.AnswerRcodes = make(map[string]int)
-
+	var err error
 	data.UptimeSeconds, err = strconv.ParseFloat(response.Data.Time.Up, 64)
 	if err != nil {
 		return data, &APICallError{
@@ -232,13 +260,57 @@ func (c *Client) FetchUnboundOverview() (UnboundDNSOverview, *APICallError) {
 			StatusCode: 0,
 		}
 	}
-	data.AnnswerBogusTotal, errConvertion = parseStringToInt(response.Data.Num.Answer.Bogus, url)
-	if errConvertion != nil {
-		return data, errConvertion
+
+	p := unboundValueParser{url: url}
+	total := response.Data.Total
+	num := response.Data.Num
+
+	data.QueryTypes = p.parseMap(num.Query.Type)
+	rcodes := make(map[string]string, len(num.Answer.Rcode))
+	for rcode, value := range num.Answer.Rcode {
+		// nodata is not a rcode but the subset of the NOERROR answers without data
+		if rcode == "nodata" {
+			data.AnswerNoData = p.parse(value)
+			continue
+		}
+		rcodes[rcode] = value
 	}
-	data.AnswerSecureTotal, errConvertion = parseStringToInt(response.Data.Num.Answer.Secure, url)
-	if errConvertion != nil {
-		return data, errConvertion
+	data.AnswerRcodes = p.parseMap(rcodes)
+	data.AnswerSecureTotal = p.parse(num.Answer.Secure)
+	data.AnswerBogusTotal = p.parse(num.Answer.Bogus)
+	data.RrsetBogusTotal = p.parse(num.Rrset.Bogus)
+
+	data.Queries = p.parse(total.Num.Queries)
+	data.QueriesIPRatelimited = p.parse(total.Num.QueriesIPRatelimited)
+	data.QueriesTimedOut = p.parse(total.Num.QueriesTimedOut)
+	data.CacheHits = p.parse(total.Num.Cachehits)
+	data.CacheMisses = p.parse(total.Num.Cachemiss)
+	data.Prefetch = p.parse(total.Num.Prefetch)
+	data.Expired = p.parse(total.Num.Expired)
+	data.RecursiveReplies = p.parse(total.Num.Recursivereplies)
+
+	data.RecursionTimeAvg = p.parse(total.Recursion.Time.Avg)
+	data.RecursionTimeMedian = p.parse(total.Recursion.Time.Median)
+
+	data.RequestListAvg = p.parse(total.Requestlist.Avg)
+	data.RequestListMax = p.parse(total.Requestlist.Max)
+	data.RequestListOverwritten = p.parse(total.Requestlist.Overwritten)
+	data.RequestListExceeded = p.parse(total.Requestlist.Exceeded)
+	data.RequestListCurrentAll = p.parse(total.Requestlist.Current.All)
+	data.RequestListCurrentUser = p.parse(total.Requestlist.Current.User)
+
+	data.MemoryCaches = p.parseMap(response.Data.Mem.Cache)
+	data.MemoryModules = p.parseMap(response.Data.Mem.Mod)
+	data.CacheEntries = p.parseMap(map[string]string{
+		"msg":   response.Data.Msg.Cache.Count,
+		"rrset": response.Data.Rrset.Cache.Count,
+		"infra": response.Data.Infra.Cache.Count,
+		"key":   response.Data.Key.Cache.Count,
+	})
+
+	if p.err != nil {
+		return data, p.err
 	}
+
 	return data, nil
 }
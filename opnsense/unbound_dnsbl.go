@@ -0,0 +1,80 @@
+package opnsense
+
+import (
+	"context"
+	"strconv"
+)
+
+type unboundTotalsResponse struct {
+	Total         int `json:"total"`
+	BlocklistSize int `json:"blocklist_size"`
+	Passed        int `json:"passed"`
+	Blocked       struct {
+		Total int `json:"total"`
+	} `json:"blocked"`
+	Top map[string]struct {
+		Total int `json:"total"`
+	} `json:"top"`
+	TopBlocked map[string]struct {
+		Total     int    `json:"total"`
+		Blocklist string `json:"blocklist"`
+	} `json:"top_blocked"`
+	TopClients map[string]struct {
+		Total int `json:"total"`
+	} `json:"top_clients"`
+}
+
+// UnboundBlocklistOverview holds the query totals of Unbound within
+// the retention period of the query statistics and the size of the blocklists.
+type UnboundBlocklistOverview struct {
+	Total         int
+	BlocklistSize int
+	Passed        int
+	Blocked       int
+	// TopBlocked holds the number of blocked queries of the most blocked domains
+	TopBlocked map[string]int
+	// TopClients holds the number of queries of the clients with the most queries
+	TopClients map[string]int
+}
+
+// FetchUnboundBlocklistOverview fetches the query totals of Unbound
+// with at most topN of the most blocked domains and the most active clients.
+// The query statistics must be enabled in Unbound.
+func (c *Client) FetchUnboundBlocklistOverview(ctx context.Context, topN int) (UnboundBlocklistOverview, *APICallError) {
+	var resp unboundTotalsResponse
+	data := UnboundBlocklistOverview{
+		TopBlocked: make(map[string]int),
+		TopClients: make(map[string]int),
+	}
+
+	path, ok := c.endpoints["unboundDNSTotals"]
+	if !ok {
+		return data, &APICallError{
+			Endpoint:   "unboundDNSTotals",
+			Message:    "endpoint not found in client endpoints",
+			StatusCode: 0,
+		}
+	}
+
+	// the API returns at least one entry of each top list
+	maximum := max(topN, 1)
+	if err := c.doWithParams(ctx, "GET", path, []string{strconv.Itoa(maximum)}, nil, &resp); err != nil {
+		return data, err
+	}
+
+	data.Total = resp.Total
+	data.BlocklistSize = resp.BlocklistSize
+	data.Passed = resp.Passed
+	data.Blocked = resp.Blocked.Total
+
+	if topN > 0 {
+		for domain, top := range resp.TopBlocked {
+			data.TopBlocked[domain] = top.Total
+		}
+		for client, top := range resp.TopClients {
+			data.TopClients[client] = top.Total
+		}
+	}
+
+	return data, nil
+}
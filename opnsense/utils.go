@@ -1,6 +1,7 @@
 package opnsense
 
 import (
+	"bytes"
 	"fmt"
 	"log/slog"
 	"regexp"
@@ -23,6 +24,33 @@ func parseStringToInt(value string, endpoint EndpointPath) (int, *APICallError)
 	return intValue, nil
 }
 
+// parseStringToFloat parses a string value to a float64 value.
+// The endpoint is used to identify the EndpointPath that the caller used.
+// so we can propagate in the *APICallError.
+func parseStringToFloat(value string, endpoint EndpointPath) (float64, *APICallError) {
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, &APICallError{
+			Endpoint:   string(endpoint),
+			Message:    fmt.Sprintf("error parsing '%s' to float: %s", value, err.Error()),
+			StatusCode: 0,
+		}
+	}
+	return floatValue, nil
+}
+
+// numericString is a value that the OPNsense API returns
+// either as a JSON string or as a JSON number.
+type numericString string
+
+func (n *numericString) UnmarshalJSON(data []byte) error {
+	*n = numericString(bytes.Trim(data, `"`))
+	if *n == "null" {
+		*n = ""
+	}
+	return nil
+}
+
 // parseStringToFloatWithReplace parses a string value to a float64 value.
 // The replace pattern is used to remove any characters that are not part of the float64 value.
 // The regex is first used to check if the value matches the regex format.
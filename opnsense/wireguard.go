@@ -1,6 +1,9 @@
 package opnsense
 
-import "log/slog"
+import (
+	"context"
+	"log/slog"
+)
 
 type wireguardRow struct {
 	IfId            string  `json:"if"`
@@ -141,7 +144,7 @@ func processWireguardResponse(rows []wireguardRow, logger *slog.Logger) Wireguar
 	return data
 }
 
-func (c *Client) FetchWireguardConfig() (WireguardClients, *APICallError) {
+func (c *Client) FetchWireguardConfig(ctx context.Context) (WireguardClients, *APICallError) {
 	var response wireguardClientsResponse
 
 	url, ok := c.endpoints["wireguardClients"]
@@ -153,7 +156,7 @@ func (c *Client) FetchWireguardConfig() (WireguardClients, *APICallError) {
 		}
 	}
 
-	if err := c.do("GET", url, nil, &response); err != nil {
+	if err := c.do(ctx, "GET", url, nil, &response); err != nil {
 		return WireguardClients{}, err
 	}
 